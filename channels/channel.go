@@ -0,0 +1,21 @@
+// Package channels defines the delivery channels notifications are sent through, such as webhooks
+// and email, and a registry of the channels enabled on this server.
+package channels
+
+import (
+	"github.com/akinolaemmanuel49/notify-api/models"
+)
+
+// Channel delivers notifications to recipients over one medium. Implement it and register it in
+// main to add a delivery target such as a pager integration.
+type Channel interface {
+	// Name identifies the channel in delivery jobs. It must be unique within a registry.
+	Name() string
+	// Capabilities describes how the channel is delivered.
+	Capabilities() models.ChannelCapabilities
+	// Targets returns the destinations a notification should be delivered to for its recipients.
+	// A recipient can have no destination, or several, on a channel.
+	Targets(notification *models.Notification, recipientIDs []int64) ([]models.DeliveryTarget, error)
+	// Send makes a single delivery attempt. Failed attempts of durable channels are retried.
+	Send(delivery *models.Delivery, notification *models.Notification) error
+}
@@ -0,0 +1,67 @@
+package channels
+
+import (
+	"net/http"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+)
+
+// ChatChannel posts notifications to chat incoming webhooks, such as Slack, Discord or Microsoft
+// Teams, registered as webhooks with the matching format.
+type ChatChannel struct {
+	name              string
+	format            string
+	formatter         ChatFormatter
+	webhookRepository *repositories.WebhookRepository
+	client            *http.Client
+}
+
+func newChatChannel(name, format string, formatter ChatFormatter, webhookRepository *repositories.WebhookRepository) *ChatChannel {
+	return &ChatChannel{
+		name:              name,
+		format:            format,
+		formatter:         formatter,
+		webhookRepository: webhookRepository,
+		client:            newWebhookClient(),
+	}
+}
+
+// NewSlackChannel delivers to Slack incoming webhooks and Slack-compatible services.
+func NewSlackChannel(webhookRepository *repositories.WebhookRepository) *ChatChannel {
+	return newChatChannel(models.ChannelSlack, models.WebhookFormatSlack, FormatSlack, webhookRepository)
+}
+
+// NewDiscordChannel delivers to Discord webhooks.
+func NewDiscordChannel(webhookRepository *repositories.WebhookRepository) *ChatChannel {
+	return newChatChannel(models.ChannelDiscord, models.WebhookFormatDiscord, FormatDiscord, webhookRepository)
+}
+
+// NewTeamsChannel delivers to Microsoft Teams incoming webhook connectors.
+func NewTeamsChannel(webhookRepository *repositories.WebhookRepository) *ChatChannel {
+	return newChatChannel(models.ChannelTeams, models.WebhookFormatTeams, FormatTeams, webhookRepository)
+}
+
+func (c *ChatChannel) Name() string {
+	return c.name
+}
+
+func (c *ChatChannel) Capabilities() models.ChannelCapabilities {
+	return models.ChannelCapabilities{Durable: true, RichText: true}
+}
+
+func (c *ChatChannel) Targets(notification *models.Notification, recipientIDs []int64) ([]models.DeliveryTarget, error) {
+	return webhookTargets(c.webhookRepository, recipientIDs, c.format, notification.Topic)
+}
+
+func (c *ChatChannel) Send(delivery *models.Delivery, notification *models.Notification) error {
+	webhook, err := loadWebhook(c.webhookRepository, delivery)
+	if webhook == nil {
+		return err
+	}
+	body, err := c.formatter(notification)
+	if err != nil {
+		return err
+	}
+	return postWebhook(c.client, c.webhookRepository, webhook, delivery, body, http.Header{})
+}
@@ -0,0 +1,293 @@
+package channels
+
+import (
+	"bytes"
+	"errors"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/mailer"
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+var emailTextTemplate = texttemplate.Must(texttemplate.New("text").Parse(
+	`Hi {{.Recipient.FirstName}},
+
+[{{.Priority}}] {{.Notification.Title}}
+
+{{.Notification.Message}}
+{{if .Notification.Topic}}
+Topic: {{.Notification.Topic}}
+{{end}}`))
+
+var emailHTMLTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(
+	`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<p>Hi {{.Recipient.FirstName}},</p>
+<h2><span style="color: #666;">[{{.Priority}}]</span> {{.Notification.Title}}</h2>
+<p style="white-space: pre-wrap;">{{.Notification.Message}}</p>
+{{if .Notification.Topic}}<p style="color: #666;">Topic: {{.Notification.Topic}}</p>{{end}}
+</body>
+</html>
+`))
+
+var digestTextTemplate = texttemplate.Must(texttemplate.New("digest-text").Parse(
+	`Hi {{.Recipient.FirstName}},
+
+You have {{len .Items}} unread notification{{if gt (len .Items) 1}}s{{end}}:
+{{range .Items}}
+[{{.Priority}}] {{.Notification.Title}}
+{{.Notification.Message}}
+{{end}}`))
+
+var digestHTMLTemplate = htmltemplate.Must(htmltemplate.New("digest-html").Parse(
+	`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<p>Hi {{.Recipient.FirstName}},</p>
+<p>You have {{len .Items}} unread notification{{if gt (len .Items) 1}}s{{end}}:</p>
+{{range .Items}}<h3><span style="color: #666;">[{{.Priority}}]</span> {{.Notification.Title}}</h3>
+<p style="white-space: pre-wrap;">{{.Notification.Message}}</p>
+{{end}}</body>
+</html>
+`))
+
+var verificationTextTemplate = texttemplate.Must(texttemplate.New("verification-text").Parse(
+	`Hi {{.Recipient.FirstName}},
+
+Please confirm your email address by sending this token to POST /api/auth/verify:
+
+{{.Token}}
+
+The token expires at {{.ExpiresAt}}. If you did not create an account, you can ignore this email.
+`))
+
+var verificationHTMLTemplate = htmltemplate.Must(htmltemplate.New("verification-html").Parse(
+	`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<p>Hi {{.Recipient.FirstName}},</p>
+<p>Please confirm your email address by sending this token to <code>POST /api/auth/verify</code>:</p>
+<p><code style="font-size: 1.2em;">{{.Token}}</code></p>
+<p style="color: #666;">The token expires at {{.ExpiresAt}}. If you did not create an account, you can ignore this email.</p>
+</body>
+</html>
+`))
+
+var passwordResetTextTemplate = texttemplate.Must(texttemplate.New("password-reset-text").Parse(
+	`Hi {{.Recipient.FirstName}},
+
+Someone asked to reset the password of your account. To choose a new password, send this token
+together with it to POST /api/auth/password-reset/confirm:
+
+{{.Token}}
+
+The token expires at {{.ExpiresAt}}. If you did not ask for a reset, you can ignore this email; your password stays the same.
+`))
+
+var passwordResetHTMLTemplate = htmltemplate.Must(htmltemplate.New("password-reset-html").Parse(
+	`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<p>Hi {{.Recipient.FirstName}},</p>
+<p>Someone asked to reset the password of your account. To choose a new password, send this token together with it to <code>POST /api/auth/password-reset/confirm</code>:</p>
+<p><code style="font-size: 1.2em;">{{.Token}}</code></p>
+<p style="color: #666;">The token expires at {{.ExpiresAt}}. If you did not ask for a reset, you can ignore this email; your password stays the same.</p>
+</body>
+</html>
+`))
+
+type emailData struct {
+	Recipient    *models.UserProfile
+	Notification *models.Notification
+	Priority     string
+}
+
+// EmailChannel emails notifications to the address stored on each recipient's account.
+type EmailChannel struct {
+	userRepository *repositories.UserRepository
+	mailer         *mailer.Mailer
+}
+
+func NewEmailChannel(userRepository *repositories.UserRepository, mailer *mailer.Mailer) *EmailChannel {
+	return &EmailChannel{
+		userRepository: userRepository,
+		mailer:         mailer,
+	}
+}
+
+func (c *EmailChannel) Name() string {
+	return models.ChannelEmail
+}
+
+func (c *EmailChannel) Capabilities() models.ChannelCapabilities {
+	return models.ChannelCapabilities{Durable: true, RichText: true}
+}
+
+func (c *EmailChannel) Targets(notification *models.Notification, recipientIDs []int64) ([]models.DeliveryTarget, error) {
+	targets := make([]models.DeliveryTarget, 0, len(recipientIDs))
+	for _, recipientID := range recipientIDs {
+		targets = append(targets, models.DeliveryTarget{RecipientID: recipientID})
+	}
+	return targets, nil
+}
+
+// Send emails a notification to its recipient. Deliveries to recipients that have since been deleted are dropped.
+func (c *EmailChannel) Send(delivery *models.Delivery, notification *models.Notification) error {
+	recipient, err := c.userRepository.GetUserByID(delivery.RecipientID)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	message, err := renderNotificationEmail(recipient, notification)
+	if err != nil {
+		return err
+	}
+	return c.mailer.Send(message)
+}
+
+// SendDigest emails a digest of notifications to its recipient as a single message.
+func (c *EmailChannel) SendDigest(recipient *models.UserProfile, digest *models.Digest) error {
+	message, err := renderDigestEmail(recipient, digest)
+	if err != nil {
+		return err
+	}
+	return c.mailer.Send(message)
+}
+
+// SendVerification emails a token that confirms the email address of an account.
+func (c *EmailChannel) SendVerification(recipient *models.UserProfile, token string, expiresAt time.Time) error {
+	message, err := renderVerificationEmail(recipient, token, expiresAt)
+	if err != nil {
+		return err
+	}
+	return c.mailer.Send(message)
+}
+
+// SendPasswordReset emails a token that lets the owner of an account choose a new password.
+func (c *EmailChannel) SendPasswordReset(recipient *models.UserProfile, token string, expiresAt time.Time) error {
+	message, err := renderPasswordResetEmail(recipient, token, expiresAt)
+	if err != nil {
+		return err
+	}
+	return c.mailer.Send(message)
+}
+
+// renderNotificationEmail renders the plain-text and HTML bodies of a notification email.
+func renderNotificationEmail(recipient *models.UserProfile, notification *models.Notification) (*mailer.Message, error) {
+	notification = notification.Localize(recipient.Locale)
+	priority, err := notification.Priority.String()
+	if err != nil {
+		return nil, err
+	}
+	data := emailData{
+		Recipient:    recipient,
+		Notification: notification,
+		Priority:     priority,
+	}
+
+	var text, html bytes.Buffer
+	if err := emailTextTemplate.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := emailHTMLTemplate.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	return &mailer.Message{
+		To:      recipient.Email,
+		Subject: "[" + priority + "] " + notification.Title,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+type digestItem struct {
+	Notification *models.Notification
+	Priority     string
+}
+
+// renderDigestEmail renders the plain-text and HTML bodies of a digest email.
+func renderDigestEmail(recipient *models.UserProfile, digest *models.Digest) (*mailer.Message, error) {
+	items := make([]digestItem, 0, len(digest.Notifications))
+	for _, notification := range digest.Notifications {
+		priority, err := notification.Priority.String()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, digestItem{Notification: notification.Localize(recipient.Locale), Priority: priority})
+	}
+	data := struct {
+		Recipient *models.UserProfile
+		Items     []digestItem
+	}{recipient, items}
+
+	var text, html bytes.Buffer
+	if err := digestTextTemplate.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := digestHTMLTemplate.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	subject := "Your daily notification digest"
+	if digest.Frequency == models.DigestWeekly {
+		subject = "Your weekly notification digest"
+	}
+	return &mailer.Message{
+		To:      recipient.Email,
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+// renderVerificationEmail renders the plain-text and HTML bodies of an email verification email.
+func renderVerificationEmail(recipient *models.UserProfile, token string, expiresAt time.Time) (*mailer.Message, error) {
+	data := struct {
+		Recipient *models.UserProfile
+		Token     string
+		ExpiresAt string
+	}{recipient, token, expiresAt.UTC().Format(time.RFC1123)}
+
+	var text, html bytes.Buffer
+	if err := verificationTextTemplate.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := verificationHTMLTemplate.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	return &mailer.Message{
+		To:      recipient.Email,
+		Subject: "Confirm your email address",
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+// renderPasswordResetEmail renders the plain-text and HTML bodies of a password reset email.
+func renderPasswordResetEmail(recipient *models.UserProfile, token string, expiresAt time.Time) (*mailer.Message, error) {
+	data := struct {
+		Recipient *models.UserProfile
+		Token     string
+		ExpiresAt string
+	}{recipient, token, expiresAt.UTC().Format(time.RFC1123)}
+
+	var text, html bytes.Buffer
+	if err := passwordResetTextTemplate.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := passwordResetHTMLTemplate.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	return &mailer.Message{
+		To:      recipient.Email,
+		Subject: "Reset your password",
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
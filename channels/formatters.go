@@ -0,0 +1,111 @@
+package channels
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+)
+
+// ChatFormatter renders a notification as the JSON payload expected by a chat service.
+type ChatFormatter func(notification *models.Notification) ([]byte, error)
+
+// priorityColors maps the labels returned by Priority.String to RGB colors.
+var priorityColors = map[string]int{
+	"LOW":  0x2E7D32,
+	"MID":  0xF9A825,
+	"HIGH": 0xD32F2F,
+}
+
+// priorityLabel returns the label and color of a notification's priority.
+func priorityLabel(notification *models.Notification) (string, int, error) {
+	label, err := notification.Priority.String()
+	if err != nil {
+		return "", 0, err
+	}
+	return label, priorityColors[label], nil
+}
+
+func hexColor(color int) string {
+	return strings.ToUpper(strconv.FormatInt(int64(color)|0x1000000, 16)[1:])
+}
+
+// slackEscaper escapes the characters Slack reserves for links and mentions.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// FormatSlack renders a Slack incoming webhook message with a colored attachment.
+func FormatSlack(notification *models.Notification) ([]byte, error) {
+	label, color, err := priorityLabel(notification)
+	if err != nil {
+		return nil, err
+	}
+	fields := []map[string]interface{}{
+		{"title": "Priority", "value": label, "short": true},
+	}
+	if notification.Topic != "" {
+		fields = append(fields, map[string]interface{}{"title": "Topic", "value": slackEscaper.Replace(notification.Topic), "short": true})
+	}
+	return json.Marshal(map[string]interface{}{
+		"text": slackEscaper.Replace("[" + label + "] " + notification.Title),
+		"attachments": []map[string]interface{}{{
+			"color":    "#" + hexColor(color),
+			"fallback": slackEscaper.Replace("[" + label + "] " + notification.Title),
+			"title":    slackEscaper.Replace(notification.Title),
+			"text":     slackEscaper.Replace(notification.Message),
+			"fields":   fields,
+			"ts":       time.Now().Unix(),
+		}},
+	})
+}
+
+// FormatDiscord renders a Discord webhook message with a colored embed.
+func FormatDiscord(notification *models.Notification) ([]byte, error) {
+	label, color, err := priorityLabel(notification)
+	if err != nil {
+		return nil, err
+	}
+	fields := []map[string]interface{}{
+		{"name": "Priority", "value": label, "inline": true},
+	}
+	if notification.Topic != "" {
+		fields = append(fields, map[string]interface{}{"name": "Topic", "value": notification.Topic, "inline": true})
+	}
+	return json.Marshal(map[string]interface{}{
+		"embeds": []map[string]interface{}{{
+			"title":       notification.Title,
+			"description": notification.Message,
+			"color":       color,
+			"fields":      fields,
+			"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		}},
+		// Never ping anyone mentioned in the notification text
+		"allowed_mentions": map[string]interface{}{"parse": []string{}},
+	})
+}
+
+// FormatTeams renders a Microsoft Teams connector card.
+func FormatTeams(notification *models.Notification) ([]byte, error) {
+	label, color, err := priorityLabel(notification)
+	if err != nil {
+		return nil, err
+	}
+	facts := []map[string]string{
+		{"name": "Priority", "value": label},
+	}
+	if notification.Topic != "" {
+		facts = append(facts, map[string]string{"name": "Topic", "value": notification.Topic})
+	}
+	return json.Marshal(map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"themeColor": hexColor(color),
+		"summary":    notification.Title,
+		"title":      "[" + label + "] " + notification.Title,
+		"text":       notification.Message,
+		"sections": []map[string]interface{}{{
+			"facts": facts,
+		}},
+	})
+}
@@ -0,0 +1,193 @@
+package channels
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// chatPriorities lists each priority with the label and RGB color chat messages show for it.
+var chatPriorities = []struct {
+	priority models.Priority
+	label    string
+	color    int
+}{
+	{models.Low, "LOW", 0x2E7D32},
+	{models.Mid, "MID", 0xF9A825},
+	{models.High, "HIGH", 0xD32F2F},
+}
+
+// receive starts a server standing in for a chat service, delivers the formatted notification to it
+// and decodes the payload it received into v.
+func receive(t *testing.T, formatter ChatFormatter, notification *models.Notification, v interface{}) {
+	t.Helper()
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		var payload json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("payload is not JSON: %v", err)
+		}
+		received = payload
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	body, err := formatter(notification)
+	if err != nil {
+		t.Fatalf("formatting: %v", err)
+	}
+	if err := post(server.Client(), server.URL, body, http.Header{}, &models.WebhookDelivery{}); err != nil {
+		t.Fatalf("posting: %v", err)
+	}
+	if err := json.Unmarshal(received, v); err != nil {
+		t.Fatalf("decoding payload: %v", err)
+	}
+}
+
+func TestFormatSlack(t *testing.T) {
+	for _, tt := range chatPriorities {
+		notification := &models.Notification{Title: "Deploy <finished>", Message: "api & web are live", Priority: tt.priority, Topic: "deploys"}
+
+		var payload struct {
+			Text        string `json:"text"`
+			Attachments []struct {
+				Color    string `json:"color"`
+				Fallback string `json:"fallback"`
+				Title    string `json:"title"`
+				Text     string `json:"text"`
+				TS       int64  `json:"ts"`
+				Fields   []struct {
+					Title string `json:"title"`
+					Value string `json:"value"`
+					Short bool   `json:"short"`
+				} `json:"fields"`
+			} `json:"attachments"`
+		}
+		receive(t, FormatSlack, notification, &payload)
+
+		if want := "[" + tt.label + "] Deploy &lt;finished&gt;"; payload.Text != want {
+			t.Errorf("%s: text = %q, want %q", tt.label, payload.Text, want)
+		}
+		if len(payload.Attachments) != 1 {
+			t.Fatalf("%s: got %d attachments, want 1", tt.label, len(payload.Attachments))
+		}
+		attachment := payload.Attachments[0]
+		if want := "#" + hexColor(tt.color); attachment.Color != want {
+			t.Errorf("%s: color = %q, want %q", tt.label, attachment.Color, want)
+		}
+		if attachment.Title != "Deploy &lt;finished&gt;" || attachment.Text != "api &amp; web are live" {
+			t.Errorf("%s: title and text are not escaped: %q, %q", tt.label, attachment.Title, attachment.Text)
+		}
+		if attachment.Fallback != payload.Text || attachment.TS == 0 {
+			t.Errorf("%s: fallback = %q and ts = %d", tt.label, attachment.Fallback, attachment.TS)
+		}
+		if len(attachment.Fields) != 2 ||
+			attachment.Fields[0].Title != "Priority" || attachment.Fields[0].Value != tt.label ||
+			attachment.Fields[1].Title != "Topic" || attachment.Fields[1].Value != "deploys" {
+			t.Errorf("%s: unexpected fields %+v", tt.label, attachment.Fields)
+		}
+	}
+}
+
+func TestFormatDiscord(t *testing.T) {
+	for _, tt := range chatPriorities {
+		notification := &models.Notification{Title: "Deploy finished", Message: "@everyone api is live", Priority: tt.priority}
+
+		var payload struct {
+			Embeds []struct {
+				Title       string `json:"title"`
+				Description string `json:"description"`
+				Color       int    `json:"color"`
+				Timestamp   string `json:"timestamp"`
+				Fields      []struct {
+					Name   string `json:"name"`
+					Value  string `json:"value"`
+					Inline bool   `json:"inline"`
+				} `json:"fields"`
+			} `json:"embeds"`
+			AllowedMentions struct {
+				Parse []string `json:"parse"`
+			} `json:"allowed_mentions"`
+		}
+		receive(t, FormatDiscord, notification, &payload)
+
+		if len(payload.Embeds) != 1 {
+			t.Fatalf("%s: got %d embeds, want 1", tt.label, len(payload.Embeds))
+		}
+		embed := payload.Embeds[0]
+		if embed.Color != tt.color {
+			t.Errorf("%s: color = %#x, want %#x", tt.label, embed.Color, tt.color)
+		}
+		if embed.Title != notification.Title || embed.Description != notification.Message || embed.Timestamp == "" {
+			t.Errorf("%s: unexpected embed %+v", tt.label, embed)
+		}
+		// Without a topic only the priority is shown
+		if len(embed.Fields) != 1 || embed.Fields[0].Name != "Priority" || embed.Fields[0].Value != tt.label {
+			t.Errorf("%s: unexpected fields %+v", tt.label, embed.Fields)
+		}
+		if payload.AllowedMentions.Parse == nil || len(payload.AllowedMentions.Parse) != 0 {
+			t.Errorf("%s: mentions are not disabled: %+v", tt.label, payload.AllowedMentions)
+		}
+	}
+}
+
+func TestFormatTeams(t *testing.T) {
+	for _, tt := range chatPriorities {
+		notification := &models.Notification{Title: "Deploy finished", Message: "api is live", Priority: tt.priority, Topic: "deploys"}
+
+		var payload struct {
+			Type       string `json:"@type"`
+			Context    string `json:"@context"`
+			ThemeColor string `json:"themeColor"`
+			Summary    string `json:"summary"`
+			Title      string `json:"title"`
+			Text       string `json:"text"`
+			Sections   []struct {
+				Facts []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"facts"`
+			} `json:"sections"`
+		}
+		receive(t, FormatTeams, notification, &payload)
+
+		if payload.Type != "MessageCard" || payload.Context != "https://schema.org/extensions" {
+			t.Errorf("%s: not a connector card: %q, %q", tt.label, payload.Type, payload.Context)
+		}
+		if want := hexColor(tt.color); payload.ThemeColor != want {
+			t.Errorf("%s: themeColor = %q, want %q", tt.label, payload.ThemeColor, want)
+		}
+		if want := "[" + tt.label + "] Deploy finished"; payload.Title != want || payload.Summary != "Deploy finished" || payload.Text != "api is live" {
+			t.Errorf("%s: unexpected title, summary or text: %q, %q, %q", tt.label, payload.Title, payload.Summary, payload.Text)
+		}
+		if len(payload.Sections) != 1 || len(payload.Sections[0].Facts) != 2 ||
+			payload.Sections[0].Facts[0].Value != tt.label || payload.Sections[0].Facts[1].Value != "deploys" {
+			t.Errorf("%s: unexpected sections %+v", tt.label, payload.Sections)
+		}
+	}
+}
+
+func TestHexColor(t *testing.T) {
+	for color, want := range map[int]string{0x2E7D32: "2E7D32", 0x00000F: "00000F", 0xD32F2F: "D32F2F"} {
+		if got := hexColor(color); got != want {
+			t.Errorf("hexColor(%#x) = %q, want %q", color, got, want)
+		}
+	}
+}
+
+func TestFormattersRejectInvalidPriority(t *testing.T) {
+	notification := &models.Notification{Title: "Deploy finished", Priority: models.Priority(7)}
+	for name, formatter := range map[string]ChatFormatter{"slack": FormatSlack, "discord": FormatDiscord, "teams": FormatTeams} {
+		if _, err := formatter(notification); !errors.Is(err, utils.ErrInvalidValueForPriority) {
+			t.Errorf("%s: got error %v, want ErrInvalidValueForPriority", name, err)
+		}
+	}
+}
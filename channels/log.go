@@ -0,0 +1,36 @@
+package channels
+
+import (
+	"log"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+)
+
+// LogChannel writes every notification to the server log. It is meant for development.
+type LogChannel struct{}
+
+func NewLogChannel() *LogChannel {
+	return &LogChannel{}
+}
+
+func (c *LogChannel) Name() string {
+	return models.ChannelLog
+}
+
+func (c *LogChannel) Capabilities() models.ChannelCapabilities {
+	return models.ChannelCapabilities{}
+}
+
+func (c *LogChannel) Targets(notification *models.Notification, recipientIDs []int64) ([]models.DeliveryTarget, error) {
+	targets := make([]models.DeliveryTarget, 0, len(recipientIDs))
+	for _, recipientID := range recipientIDs {
+		targets = append(targets, models.DeliveryTarget{RecipientID: recipientID})
+	}
+	return targets, nil
+}
+
+func (c *LogChannel) Send(delivery *models.Delivery, notification *models.Notification) error {
+	priority, _ := notification.Priority.String()
+	log.Printf("Notification %d for user %d: [%s] %s: %s\n", notification.ID, delivery.RecipientID, priority, notification.Title, notification.Message)
+	return nil
+}
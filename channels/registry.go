@@ -0,0 +1,38 @@
+package channels
+
+import (
+	"fmt"
+)
+
+// Registry holds the channels notifications are dispatched to, in registration order.
+type Registry struct {
+	channels map[string]Channel
+	order    []Channel
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		channels: map[string]Channel{},
+	}
+}
+
+// Register adds a channel. Registering two channels with the same name is an error.
+func (r *Registry) Register(channel Channel) error {
+	if _, ok := r.channels[channel.Name()]; ok {
+		return fmt.Errorf("channel %q is already registered", channel.Name())
+	}
+	r.channels[channel.Name()] = channel
+	r.order = append(r.order, channel)
+	return nil
+}
+
+// Get returns the channel registered under name.
+func (r *Registry) Get(name string) (Channel, bool) {
+	channel, ok := r.channels[name]
+	return channel, ok
+}
+
+// Channels returns every registered channel.
+func (r *Registry) Channels() []Channel {
+	return r.order
+}
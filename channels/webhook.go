@@ -0,0 +1,197 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// webhookTimeout bounds a single webhook delivery attempt.
+const webhookTimeout = 10 * time.Second
+
+// WebhookChannel POSTs signed JSON payloads to the webhooks registered by recipients.
+type WebhookChannel struct {
+	webhookRepository *repositories.WebhookRepository
+	client            *http.Client
+}
+
+func NewWebhookChannel(webhookRepository *repositories.WebhookRepository) *WebhookChannel {
+	return &WebhookChannel{
+		webhookRepository: webhookRepository,
+		client:            newWebhookClient(),
+	}
+}
+
+// errNonPublicAddress is returned when a webhook host resolves to an address that is not public.
+var errNonPublicAddress = errors.New("webhook host resolves to a non-public address")
+
+// newWebhookClient returns the client webhooks are called with. Webhook URLs are user supplied, so it
+// only connects to public addresses, checked after DNS resolution so that a host name cannot be
+// pointed at internal services, and it does not follow redirects.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !utils.IsPublicIP(addrPort.Addr()) {
+				return errNonPublicAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: webhookTimeout,
+		// Connect directly, a proxy would hide the address the request ends up at
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, address)
+			},
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		// A redirect is reported as a failed delivery with its status code
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func (c *WebhookChannel) Name() string {
+	return models.ChannelWebhook
+}
+
+func (c *WebhookChannel) Capabilities() models.ChannelCapabilities {
+	return models.ChannelCapabilities{Durable: true, Signed: true}
+}
+
+func (c *WebhookChannel) Targets(notification *models.Notification, recipientIDs []int64) ([]models.DeliveryTarget, error) {
+	return webhookTargets(c.webhookRepository, recipientIDs, models.WebhookFormatJSON, notification.Topic)
+}
+
+func (c *WebhookChannel) Send(delivery *models.Delivery, notification *models.Notification) error {
+	webhook, err := loadWebhook(c.webhookRepository, delivery)
+	if webhook == nil {
+		return err
+	}
+
+	body, header, err := signedPayload(webhook, notification, time.Now())
+	if err != nil {
+		return err
+	}
+	return postWebhook(c.client, c.webhookRepository, webhook, delivery, body, header)
+}
+
+// signedPayload returns the WebhookPayload of a notification and the headers that sign it with the
+// webhook secret.
+func signedPayload(webhook *models.Webhook, notification *models.Notification, now time.Time) ([]byte, http.Header, error) {
+	timestamp := now.Unix()
+	body, err := json.Marshal(models.WebhookPayload{
+		Event:       models.WebhookEventNotificationCreated,
+		WebhookID:   webhook.ID,
+		RecipientID: webhook.UserID,
+		Timestamp:   timestamp,
+		Data:        notification,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set(utils.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	header.Set(utils.SignatureHeader, utils.SignPayload(webhook.Secret, timestamp, body))
+	return body, header, nil
+}
+
+// webhookTargets returns a target for every active webhook of the given format owned by a recipient
+// that accepts notifications on topic.
+func webhookTargets(webhookRepository *repositories.WebhookRepository, recipientIDs []int64, format, topic string) ([]models.DeliveryTarget, error) {
+	webhooks, err := webhookRepository.GetActiveWebhooks(recipientIDs, format, topic)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]models.DeliveryTarget, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookID := webhook.ID
+		targets = append(targets, models.DeliveryTarget{RecipientID: webhook.UserID, TargetID: &webhookID})
+	}
+	return targets, nil
+}
+
+// loadWebhook fetches the webhook a delivery targets. It returns a nil webhook and no error when the
+// webhook has since been deleted or deactivated, so the delivery is dropped.
+func loadWebhook(webhookRepository *repositories.WebhookRepository, delivery *models.Delivery) (*models.Webhook, error) {
+	if delivery.TargetID == nil {
+		return nil, nil
+	}
+	webhook, err := webhookRepository.GetWebhookByID(*delivery.TargetID)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !webhook.Active {
+		return nil, nil
+	}
+	return webhook, nil
+}
+
+// postWebhook POSTs a JSON body to a webhook and records the attempt in its delivery log.
+func postWebhook(client *http.Client, webhookRepository *repositories.WebhookRepository, webhook *models.Webhook, delivery *models.Delivery, body []byte, header http.Header) error {
+	record := &models.WebhookDelivery{
+		WebhookID:      webhook.ID,
+		NotificationID: &delivery.NotificationID,
+		Attempt:        delivery.Attempt,
+	}
+	err := post(client, webhook.URL, body, header, record)
+	if err != nil {
+		message := err.Error()
+		record.Error = &message
+	} else {
+		record.Success = true
+	}
+	if err := webhookRepository.CreateWebhookDelivery(record); err != nil {
+		log.Println("Error recording webhook delivery:", err)
+	}
+	return err
+}
+
+func post(client *http.Client, url string, body []byte, header http.Header, record *models.WebhookDelivery) error {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header = header
+	request.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	response, err := client.Do(request)
+	record.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	record.StatusCode = &response.StatusCode
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+	return nil
+}
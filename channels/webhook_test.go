@@ -0,0 +1,97 @@
+package channels
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// newReceiver starts a webhook receiver that verifies signatures with secret, as receivers are
+// told to.
+func newReceiver(t *testing.T, secret string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading body: %v", err)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", r.Header.Get("Content-Type"))
+		}
+		if r.Header.Get(utils.TimestampHeader) == "" {
+			t.Errorf("%s header is missing", utils.TimestampHeader)
+		}
+		err = utils.VerifySignature(secret, r.Header.Get(utils.SignatureHeader), body, 5*time.Minute, time.Now())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSignedPayloadIsVerifiedByReceiver(t *testing.T) {
+	server := newReceiver(t, "receiver-secret")
+	notification := &models.Notification{ID: 7, Title: "Deploy finished", PublisherID: 1}
+
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{"matching secret", "receiver-secret", false},
+		{"other secret", "another-secret", true},
+	}
+	for _, tt := range tests {
+		webhook := &models.Webhook{ID: 1, UserID: 2, URL: server.URL, Secret: tt.secret}
+		body, header, err := signedPayload(webhook, notification, time.Now())
+		if err != nil {
+			t.Fatalf("%s: signedPayload: %v", tt.name, err)
+		}
+		record := &models.WebhookDelivery{}
+		err = post(server.Client(), server.URL, body, header, record)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: post error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWebhookClientRefusesNonPublicAddresses(t *testing.T) {
+	server := newReceiver(t, "secret")
+
+	err := post(newWebhookClient(), server.URL, []byte("{}"), http.Header{}, &models.WebhookDelivery{})
+	if !errors.Is(err, errNonPublicAddress) {
+		t.Fatalf("got error %v, want errNonPublicAddress", err)
+	}
+}
+
+func TestWebhookClientDoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer server.Close()
+
+	// Test servers listen on loopback, so only the redirect policy of the client is used
+	client := newWebhookClient()
+	client.Transport = http.DefaultTransport
+
+	record := &models.WebhookDelivery{}
+	err := post(client, server.URL, []byte("{}"), http.Header{}, record)
+	if err == nil {
+		t.Fatal("expected a redirect to fail the delivery")
+	}
+	if followed {
+		t.Error("redirect was followed")
+	}
+	if record.StatusCode == nil || *record.StatusCode != http.StatusTemporaryRedirect {
+		t.Errorf("recorded status %v, want %d", record.StatusCode, http.StatusTemporaryRedirect)
+	}
+}
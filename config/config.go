@@ -15,13 +15,63 @@ type Config struct {
 		Name string `yaml:"name" envconfig:"DB_NAME"`
 	} `yaml:"database"`
 	JWT struct {
-		Key       string `yaml:"key" envconfig:"JWT_KEY"`
-		Token_TTL string `yaml:"tokenTTL" envconfig:"JWT_TOKEN_TTL"`
+		Key             string `yaml:"key" envconfig:"JWT_KEY"`
+		Token_TTL       string `yaml:"tokenTTL" envconfig:"JWT_TOKEN_TTL"`
+		RefreshTokenTTL string `yaml:"refreshTokenTTL" envconfig:"JWT_REFRESH_TOKEN_TTL"`
+		// Algorithm is HS256 (signed with Key), RS256 or EdDSA (signed with the private key at
+		// SigningKey). VerificationKeys is a comma separated list of public key files of retired
+		// signing keys whose tokens are still accepted.
+		Algorithm        string `yaml:"algorithm" envconfig:"JWT_ALGORITHM"`
+		SigningKey       string `yaml:"signingKey" envconfig:"JWT_SIGNING_KEY"`
+		VerificationKeys string `yaml:"verificationKeys" envconfig:"JWT_VERIFICATION_KEYS"`
 	} `yaml:"jwt"`
 	RateLimiting struct {
 		MaxRequests string `yaml:"maxRequests" envconfig:"MAX_REQUESTS"`
 		Duration    string `yaml:"duration" envconfig:"REQUEST_LIMIT_DURATION"`
 	} `yaml:"rateLimiting"`
+	Scheduler struct {
+		Interval      string `yaml:"interval" envconfig:"SCHEDULER_INTERVAL"`
+		CatchUpPolicy string `yaml:"catchUpPolicy" envconfig:"SCHEDULER_CATCH_UP_POLICY"`
+	} `yaml:"scheduler"`
+	Janitor struct {
+		Interval string `yaml:"interval" envconfig:"JANITOR_INTERVAL"`
+		Mode     string `yaml:"mode" envconfig:"JANITOR_MODE"`
+	} `yaml:"janitor"`
+	Delivery struct {
+		Interval    string `yaml:"interval" envconfig:"DELIVERY_INTERVAL"`
+		MaxAttempts string `yaml:"maxAttempts" envconfig:"DELIVERY_MAX_ATTEMPTS"`
+	} `yaml:"delivery"`
+	QuietHours struct {
+		Interval string `yaml:"interval" envconfig:"QUIET_HOURS_INTERVAL"`
+	} `yaml:"quietHours"`
+	Digest struct {
+		Interval string `yaml:"interval" envconfig:"DIGEST_INTERVAL"`
+	} `yaml:"digest"`
+	Idempotency struct {
+		Retention string `yaml:"retention" envconfig:"IDEMPOTENCY_RETENTION"`
+		Lease     string `yaml:"lease" envconfig:"IDEMPOTENCY_LEASE"`
+	} `yaml:"idempotency"`
+	Password struct {
+		MinLength    string `yaml:"minLength" envconfig:"PASSWORD_MIN_LENGTH"`
+		BreachedList string `yaml:"breachedList" envconfig:"PASSWORD_BREACHED_LIST"`
+	} `yaml:"password"`
+	Verification struct {
+		TokenTTL string `yaml:"tokenTTL" envconfig:"VERIFICATION_TOKEN_TTL"`
+	} `yaml:"verification"`
+	PasswordReset struct {
+		TokenTTL string `yaml:"tokenTTL" envconfig:"PASSWORD_RESET_TOKEN_TTL"`
+	} `yaml:"passwordReset"`
+	Mail struct {
+		Host     string `yaml:"host" envconfig:"MAIL_HOST"`
+		Port     string `yaml:"port" envconfig:"MAIL_PORT"`
+		Username string `yaml:"username" envconfig:"MAIL_USERNAME"`
+		Password string `yaml:"password" envconfig:"MAIL_PASSWORD"`
+		From     string `yaml:"from" envconfig:"MAIL_FROM"`
+		StartTLS string `yaml:"startTLS" envconfig:"MAIL_STARTTLS"`
+	} `yaml:"mail"`
+	Channels struct {
+		Enabled string `yaml:"enabled" envconfig:"CHANNELS_ENABLED"`
+	} `yaml:"channels"`
 }
 
 func processError(err error) {
@@ -0,0 +1,231 @@
+// Package cron parses standard 5-field cron expressions and computes their firing times.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression. Each field is a bitset of the values it matches.
+type Schedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// When both day-of-month and day-of-week are restricted, a day matches if either does.
+	domRestricted bool
+	dowRestricted bool
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week accepts 7 as an alias for Sunday.
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxSearchYears bounds the search for the next firing of expressions such as "0 0 30 2 *" that never fire.
+const maxSearchYears = 5
+
+// ErrNoFiring is returned by Next when an expression never fires.
+var ErrNoFiring = errors.New("cron expression never fires")
+
+// Parse parses a standard 5-field cron expression ("minute hour day-of-month month day-of-week").
+// Fields accept "*", single values, ranges ("1-5"), steps ("*/15", "0-30/10"), comma separated lists
+// and three-letter month and weekday names. The descriptors @yearly, @monthly, @weekly, @daily and
+// @hourly are also accepted.
+func Parse(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if descriptor, ok := descriptors[strings.ToLower(expression)]; ok {
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	var schedule Schedule
+	var err error
+	if schedule.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if schedule.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if schedule.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// Fold Sunday as 7 into Sunday as 0
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow = (schedule.dow | 1) &^ (1 << 7)
+	}
+	// As in Vixie cron, a field starting with "*" (including steps such as "*/2") is unrestricted
+	schedule.domRestricted = !strings.HasPrefix(fields[2], "*")
+	schedule.dowRestricted = !strings.HasPrefix(fields[4], "*")
+	return &schedule, nil
+}
+
+func (f field) parse(expression string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(expression, ",") {
+		bits, err := f.parsePart(part)
+		if err != nil {
+			return 0, err
+		}
+		set |= bits
+	}
+	return set, nil
+}
+
+func (f field) parsePart(part string) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step < 1 {
+			return 0, fmt.Errorf("invalid step %q in %s field", stepPart, f.name)
+		}
+	}
+
+	var start, end int
+	switch {
+	case rangePart == "*":
+		start, end = f.min, f.max
+	case strings.Contains(rangePart, "-"):
+		low, high, _ := strings.Cut(rangePart, "-")
+		var err error
+		if start, err = f.value(low); err != nil {
+			return 0, err
+		}
+		if end, err = f.value(high); err != nil {
+			return 0, err
+		}
+		if start > end {
+			return 0, fmt.Errorf("invalid range %q in %s field", rangePart, f.name)
+		}
+	default:
+		var err error
+		if start, err = f.value(rangePart); err != nil {
+			return 0, err
+		}
+		end = start
+		// "5/15" means every 15 starting at 5
+		if hasStep {
+			end = f.max
+		}
+	}
+
+	var set uint64
+	for value := start; value <= end; value += step {
+		set |= 1 << uint(value)
+	}
+	return set, nil
+}
+
+func (f field) value(token string) (int, error) {
+	if value, ok := f.names[strings.ToLower(token)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", token, f.name)
+	}
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d] in %s field", value, f.min, f.max, f.name)
+	}
+	return value, nil
+}
+
+// Next returns the first firing time strictly after t, in t's location.
+//
+// Expressions match wall-clock times in t's location. When a daylight saving change skips a time,
+// its firing happens as much later as the clocks moved forward (02:30 becomes 03:30). When a change
+// repeats times, they only fire the first time around.
+func (s *Schedule) Next(t time.Time) (time.Time, error) {
+	loc := t.Location()
+	// Search over wall-clock times, represented in UTC so that the search itself never meets a
+	// daylight saving change. Firings happen on whole minutes.
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC).Add(time.Minute)
+	limit := wall.AddDate(maxSearchYears, 0, 0)
+
+	for wall.Before(limit) {
+		if s.month&(1<<uint(wall.Month())) == 0 {
+			wall = time.Date(wall.Year(), wall.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.matchesDay(wall) {
+			wall = time.Date(wall.Year(), wall.Month(), wall.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.hour&(1<<uint(wall.Hour())) == 0 {
+			wall = wall.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if s.minute&(1<<uint(wall.Minute())) == 0 {
+			wall = wall.Add(time.Minute)
+			continue
+		}
+		next := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, loc)
+		if next.Hour() != wall.Hour() || next.Minute() != wall.Minute() {
+			// The time was skipped by a daylight saving change. time.Date resolves it with one of
+			// the offsets around the change; the later of the two moves it forward.
+			_, offset := next.Zone()
+			if shifted := wall.Add(-time.Duration(offset) * time.Second).In(loc); shifted.After(next) {
+				next = shifted
+			}
+		}
+		// A wall-clock time repeated by a daylight saving change maps to its first occurrence,
+		// which can be before t when t is in the repeated hour
+		if !next.After(t) {
+			wall = wall.Add(time.Minute)
+			continue
+		}
+		return next, nil
+	}
+	return time.Time{}, ErrNoFiring
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
@@ -0,0 +1,210 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s is not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"1,,2 * * * *",
+		"@every 5m",
+	} {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("Parse(%q): expected an error", expression)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Monday 15 January 2024, 10:07:30 UTC
+	from := time.Date(2024, time.January, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		want       []string
+	}{
+		{"* * * * *", []string{"2024-01-15T10:08:00Z", "2024-01-15T10:09:00Z"}},
+		{"*/15 * * * *", []string{"2024-01-15T10:15:00Z", "2024-01-15T10:30:00Z", "2024-01-15T10:45:00Z", "2024-01-15T11:00:00Z"}},
+		{"5/20 * * * *", []string{"2024-01-15T10:25:00Z", "2024-01-15T10:45:00Z", "2024-01-15T11:05:00Z"}},
+		{"0-30/10 9-10 * * *", []string{"2024-01-15T10:10:00Z", "2024-01-15T10:20:00Z", "2024-01-15T10:30:00Z", "2024-01-16T09:00:00Z"}},
+		{"0 9,17 * * *", []string{"2024-01-15T17:00:00Z", "2024-01-16T09:00:00Z"}},
+		{"0 9 * * mon-fri", []string{"2024-01-16T09:00:00Z", "2024-01-17T09:00:00Z", "2024-01-18T09:00:00Z", "2024-01-19T09:00:00Z", "2024-01-22T09:00:00Z"}},
+		{"0 0 * * SUN", []string{"2024-01-21T00:00:00Z", "2024-01-28T00:00:00Z"}},
+		{"0 0 * * 7", []string{"2024-01-21T00:00:00Z"}},
+		{"0 0 1 jan-mar *", []string{"2024-02-01T00:00:00Z", "2024-03-01T00:00:00Z", "2025-01-01T00:00:00Z"}},
+		{"0 0 29 2 *", []string{"2024-02-29T00:00:00Z", "2028-02-29T00:00:00Z"}},
+		{"0 0 31 * *", []string{"2024-01-31T00:00:00Z", "2024-03-31T00:00:00Z", "2024-05-31T00:00:00Z"}},
+		// Day of month and day of week both restricted: either may match
+		{"0 0 13 * fri", []string{"2024-01-19T00:00:00Z", "2024-01-26T00:00:00Z", "2024-02-02T00:00:00Z", "2024-02-09T00:00:00Z", "2024-02-13T00:00:00Z"}},
+		// A starred step leaves day of month unrestricted: both must match
+		{"0 0 */2 * mon", []string{"2024-01-29T00:00:00Z", "2024-02-05T00:00:00Z"}},
+		{"@hourly", []string{"2024-01-15T11:00:00Z"}},
+		{"@daily", []string{"2024-01-16T00:00:00Z"}},
+		{"@weekly", []string{"2024-01-21T00:00:00Z"}},
+		{"@monthly", []string{"2024-02-01T00:00:00Z"}},
+		{"@YEARLY", []string{"2025-01-01T00:00:00Z"}},
+	}
+	for _, tt := range tests {
+		schedule, err := Parse(tt.expression)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expression, err)
+			continue
+		}
+		next := from
+		for _, want := range tt.want {
+			next, err = schedule.Next(next)
+			if err != nil {
+				t.Errorf("%q: Next: %v", tt.expression, err)
+				break
+			}
+			if got := next.Format(time.RFC3339); got != want {
+				t.Errorf("%q: got %s, want %s", tt.expression, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestNextIsStrictlyAfter(t *testing.T) {
+	schedule, err := Parse("30 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, time.January, 15, 9, 30, 0, 0, time.UTC)
+	next, err := schedule.Next(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := at.AddDate(0, 0, 1); !next.Equal(want) {
+		t.Errorf("got %s, want %s", next, want)
+	}
+}
+
+func TestNextNeverFires(t *testing.T) {
+	for _, expression := range []string{"0 0 30 2 *", "0 0 31 4,6,9,11 *"} {
+		schedule, err := Parse(expression)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", expression, err)
+		}
+		if _, err := schedule.Next(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrNoFiring) {
+			t.Errorf("%q: got error %v, want ErrNoFiring", expression, err)
+		}
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	loc := mustLoadLocation(t, "Asia/Kolkata")
+	schedule, err := Parse("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 03:30 UTC is 09:00 in Kolkata, which is 5:30 ahead
+	next, err := schedule.Next(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC).In(loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, time.January, 15, 3, 30, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("got %s, want %s", next.UTC(), want)
+	}
+	if next.Location() != loc {
+		t.Errorf("got location %s, want %s", next.Location(), loc)
+	}
+}
+
+func TestNextAcrossDaylightSavingChanges(t *testing.T) {
+	loc := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name       string
+		expression string
+		from       time.Time
+		want       []string
+	}{
+		{
+			// Clocks go from 02:00 EST to 03:00 EDT on 10 March 2024
+			"skipped time fires after the change",
+			"30 2 * * *",
+			time.Date(2024, time.March, 9, 12, 0, 0, 0, loc),
+			[]string{"2024-03-10T03:30:00-04:00", "2024-03-11T02:30:00-04:00"},
+		},
+		{
+			"daily time keeps its wall-clock time",
+			"0 9 * * *",
+			time.Date(2024, time.March, 9, 12, 0, 0, 0, loc),
+			[]string{"2024-03-10T09:00:00-04:00", "2024-03-11T09:00:00-04:00"},
+		},
+		{
+			"hourly spring forward",
+			"0 * * * *",
+			time.Date(2024, time.March, 10, 0, 30, 0, 0, loc),
+			[]string{"2024-03-10T01:00:00-05:00", "2024-03-10T03:00:00-04:00", "2024-03-10T04:00:00-04:00"},
+		},
+		{
+			// Clocks go from 02:00 EDT back to 01:00 EST on 3 November 2024
+			"repeated time fires once",
+			"30 1 * * *",
+			time.Date(2024, time.November, 2, 12, 0, 0, 0, loc),
+			[]string{"2024-11-03T01:30:00-04:00", "2024-11-04T01:30:00-05:00"},
+		},
+		{
+			"hourly fall back",
+			"0 * * * *",
+			time.Date(2024, time.November, 3, 0, 30, 0, 0, loc),
+			[]string{"2024-11-03T01:00:00-04:00", "2024-11-03T02:00:00-05:00", "2024-11-03T03:00:00-05:00"},
+		},
+	}
+	for _, tt := range tests {
+		schedule, err := Parse(tt.expression)
+		if err != nil {
+			t.Fatalf("%s: Parse: %v", tt.name, err)
+		}
+		next := tt.from
+		for _, want := range tt.want {
+			next, err = schedule.Next(next)
+			if err != nil {
+				t.Errorf("%s: Next: %v", tt.name, err)
+				break
+			}
+			if got := next.Format(time.RFC3339); got != want {
+				t.Errorf("%s: got %s, want %s", tt.name, got, want)
+				break
+			}
+		}
+	}
+
+	// Starting inside the repeated hour does not go back to its first occurrence
+	schedule, _ := Parse("45 1 * * *")
+	from := time.Date(2024, time.November, 3, 6, 10, 0, 0, time.UTC).In(loc) // 01:10 EST
+	next, err := schedule.Next(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2024-11-04T01:45:00-05:00"; next.Format(time.RFC3339) != want {
+		t.Errorf("from the repeated hour: got %s, want %s", next.Format(time.RFC3339), want)
+	}
+}
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/services"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+type AdminHandler struct {
+	deliveryService     *services.DeliveryService
+	verificationService *services.VerificationService
+}
+
+func NewAdminHandler(deliveryService *services.DeliveryService, verificationService *services.VerificationService) *AdminHandler {
+	return &AdminHandler{
+		deliveryService:     deliveryService,
+		verificationService: verificationService,
+	}
+}
+
+func (h *AdminHandler) GetDeliveryJobs(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetDeliveryJobs")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	// Check the page query in the url, convert it to an integer, resolve errors
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Check the pageSize query in the url, convert it to an integer, resolve errors
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10 // default page size
+	}
+
+	// Only return jobs that have failed at least once when failing=true
+	failingOnly, _ := strconv.ParseBool(r.URL.Query().Get("failing"))
+
+	jobs, err := h.deliveryService.GetJobs(userID, failingOnly, page, pageSize)
+
+	// Check and resolve errors from get jobs service
+	if err != nil {
+		if errors.Is(err, utils.ErrAdminRequired) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve delivery jobs: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AdminResponse{
+		Code:    http.StatusOK,
+		Data:    jobs,
+		Message: "Delivery jobs successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AdminHandler) GetDeadLetterJobs(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetDeadLetterJobs")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	// Check the page query in the url, convert it to an integer, resolve errors
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Check the pageSize query in the url, convert it to an integer, resolve errors
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10 // default page size
+	}
+
+	jobs, err := h.deliveryService.GetDeadLetterJobs(userID, page, pageSize)
+
+	// Check and resolve errors from get dead-letter jobs service
+	if err != nil {
+		if errors.Is(err, utils.ErrAdminRequired) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve dead-letter jobs: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AdminResponse{
+		Code:    http.StatusOK,
+		Data:    jobs,
+		Message: "Dead-letter jobs successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AdminHandler) GetDeadLetterJobByID(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetDeadLetterJobByID")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid dead-letter job ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	job, err := h.deliveryService.GetDeadLetterJobByID(ID, userID)
+
+	// Check and resolve errors from get dead-letter job by id service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: dead-letter job with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrAdminRequired) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve dead-letter job: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AdminResponse{
+		Code:    http.StatusOK,
+		Data:    job,
+		Message: fmt.Sprintf("Dead-letter job with ID: %d was successfully retrieved", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AdminHandler) ReplayDeadLetterJob(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: ReplayDeadLetterJob")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid dead-letter job ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	job, err := h.deliveryService.ReplayDeadLetterJob(ID, userID)
+
+	// Check and resolve errors from replay dead-letter job service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: dead-letter job with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrAdminRequired) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to replay dead-letter job: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AdminResponse{
+		Code:    http.StatusOK,
+		Data:    job,
+		Message: fmt.Sprintf("Dead-letter job with ID: %d was queued again", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AdminHandler) DeleteDeadLetterJobByID(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: DeleteDeadLetterJobByID")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid dead-letter job ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	err = h.deliveryService.DeleteDeadLetterJobByID(ID, userID)
+
+	// Check and resolve errors from delete dead-letter job service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: dead-letter job with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrAdminRequired) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AdminResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Dead-letter job with ID: %d was successfully deleted", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AdminHandler) VerifyUserEmail(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: VerifyUserEmail")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	err = h.verificationService.MarkEmailVerified(ID, userID)
+
+	// Check and resolve errors from mark email verified service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: user with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrAdminRequired) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to verify email address: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AdminResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Email address of user with ID: %d was verified", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
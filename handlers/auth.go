@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/akinolaemmanuel49/notify-api/models"
 	"github.com/akinolaemmanuel49/notify-api/services"
 	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type AuthHandler struct {
@@ -43,12 +45,78 @@ func (h *AuthHandler) GenerateToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if ok {
-		token, err := utils.GenerateJWT(ID)
+		tokenPair, err := h.authService.IssueTokens(ID)
 		if err != nil {
 			errorMessage := fmt.Sprintf("Error: failed to generate token: %s", err.Error())
 			utils.RespondWithError(w, errorMessage, http.StatusInternalServerError)
 			return
 		}
-		json.NewEncoder(w).Encode(map[string]string{"token": token})
+		json.NewEncoder(w).Encode(tokenPair)
 	}
 }
+
+func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: RefreshToken")
+
+	var refreshRequest models.RefreshRequest
+
+	// Check and resolve errors during JSON decoding process
+	err := json.NewDecoder(r.Body).Decode(&refreshRequest)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	tokenPair, err := h.authService.RefreshTokens(refreshRequest.RefreshToken)
+
+	// Check and resolve errors from refresh tokens service
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidRefreshToken) || errors.Is(err, utils.ErrRefreshTokenReused) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusUnauthorized)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to refresh token: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(tokenPair)
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: Logout")
+
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+
+	err = h.authService.Logout(token.Claims.(jwt.MapClaims))
+	if err != nil {
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to log out: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AuthResponse{
+		Code:    http.StatusOK,
+		Message: "Successfully logged out",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AuthHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetJWKS")
+
+	jwks := models.JSONWebKeySet{
+		Keys: utils.JSONWebKeys(),
+	}
+
+	// Keys only change on restart, so verifiers may cache them briefly
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(jwks)
+}
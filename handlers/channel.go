@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/services"
+)
+
+type ChannelHandler struct {
+	deliveryService *services.DeliveryService
+}
+
+func NewChannelHandler(deliveryService *services.DeliveryService) *ChannelHandler {
+	return &ChannelHandler{
+		deliveryService: deliveryService,
+	}
+}
+
+func (h *ChannelHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetChannels")
+
+	response := models.ChannelResponse{
+		Code:    http.StatusOK,
+		Data:    h.deliveryService.GetChannels(),
+		Message: "Channels successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/services"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// responseRecorder passes a response through while keeping a copy of its status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// handleIdempotently calls handle once per Idempotency-Key sent by a user and replays its response
+// to repeated requests. Requests without the header are handled as usual. Server errors are not
+// stored, so that the request can be retried with the same key.
+func handleIdempotently(idempotencyService *services.IdempotencyService, w http.ResponseWriter, r *http.Request, userID int64, body []byte, handle func(w http.ResponseWriter)) {
+	key := r.Header.Get(models.IdempotencyKeyHeader)
+	if key == "" {
+		handle(w)
+		return
+	}
+
+	idempotencyKey, err := idempotencyService.Begin(userID, key, r.Method, r.URL.Path, body)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidIdempotencyKey) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, utils.ErrIdempotencyKeyMismatch) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, utils.ErrIdempotencyKeyInUse) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusConflict)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to check idempotency key: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	if idempotencyKey.Completed() {
+		for name, values := range idempotencyKey.ResponseHeaders {
+			w.Header()[name] = values
+		}
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(*idempotencyKey.ResponseStatus)
+		if idempotencyKey.ResponseBody != nil {
+			w.Write([]byte(*idempotencyKey.ResponseBody))
+		}
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: w}
+	handle(recorder)
+	if recorder.status == 0 || recorder.status >= http.StatusInternalServerError {
+		if err := idempotencyService.Release(idempotencyKey); err != nil {
+			log.Println("Error releasing idempotency key:", err)
+		}
+		return
+	}
+	if err := idempotencyService.Complete(idempotencyKey, recorder.status, w.Header().Clone(), recorder.body.String()); err != nil {
+		log.Println("Error storing idempotent response:", err)
+		// A key left without a response would turn every retry away as in progress
+		if err := idempotencyService.Release(idempotencyKey); err != nil {
+			log.Println("Error releasing idempotency key:", err)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -17,11 +18,13 @@ import (
 
 type NotificationHandler struct {
 	notificationService *services.NotificationService
+	idempotencyService  *services.IdempotencyService
 }
 
-func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+func NewNotificationHandler(notificationService *services.NotificationService, idempotencyService *services.IdempotencyService) *NotificationHandler {
 	return &NotificationHandler{
 		notificationService: notificationService,
+		idempotencyService:  idempotencyService,
 	}
 }
 
@@ -47,32 +50,59 @@ func (h *NotificationHandler) CreateNotification(w http.ResponseWriter, r *http.
 	claims := token.Claims.(jwt.MapClaims)
 	publisherID := int64(claims["id"].(float64))
 
+	// The body is read up front so that retries with an Idempotency-Key can be compared with it
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	handleIdempotently(h.idempotencyService, w, r, publisherID, body, func(w http.ResponseWriter) {
+		h.createNotification(w, body, publisherID)
+	})
+}
+
+func (h *NotificationHandler) createNotification(w http.ResponseWriter, body []byte, publisherID int64) {
 	var notificationInput models.NotificationInput
 
 	// Check and resolve errors during JSON decoding process
-	err = json.NewDecoder(r.Body).Decode(&notificationInput)
+	err := json.Unmarshal(body, &notificationInput)
 	if err != nil {
 		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
 		return
 	}
 
 	// Check and resolve errors from the create notification service
-	err = h.notificationService.CreateNotification(&notificationInput, publisherID)
+	notification, err := h.notificationService.CreateNotification(&notificationInput, publisherID)
 	if err != nil {
+		if errors.Is(err, utils.ErrEmailNotVerified) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
 		if errors.Is(err, utils.ErrInvalidRangeForPriority) {
 			utils.RespondWithError(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, utils.ErrRecipientNotFound) || errors.Is(err, utils.ErrInvalidTopicName) || errors.Is(err, utils.ErrInvalidExpiry) || errors.Is(err, utils.ErrInvalidLocale) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if isTemplateRenderError(err) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
 		utils.RespondWithError(w, fmt.Sprintf("Error: failed to create notification: %s", err.Error()), http.StatusInternalServerError)
 		return
 	}
 
 	response := models.NotificationResponse{
 		Code:    http.StatusCreated,
-		Message: "Notification was successfully created",
+		Data:    notification,
+		Message: fmt.Sprintf("Notification with ID: %d was successfully created", notification.ID),
 	}
 
 	// Write response header
+	w.Header().Set("Location", fmt.Sprintf("/api/notifications/%d", notification.ID))
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
 }
@@ -91,7 +121,16 @@ func (h *NotificationHandler) GetNotificationByID(w http.ResponseWriter, r *http
 		return
 	}
 
-	notification, err := h.notificationService.GetNotificationByID(ID)
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	notification, err := h.notificationService.GetNotificationByID(ID, userID)
 
 	// Check and resolve errors from get notification by id service
 	if err != nil {
@@ -155,9 +194,211 @@ func (h *NotificationHandler) GetOwnNotifications(w http.ResponseWriter, r *http
 	json.NewEncoder(w).Encode(response)
 }
 
+func (h *NotificationHandler) GetInbox(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetInbox")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	recipientID := int64(claims["id"].(float64))
+
+	// Check the page query in the url, convert it to an integer, resolve errors
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Check the pageSize query in the url, convert it to an integer, resolve errors
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10 // default page size
+	}
+
+	// Only return unread notifications when requested
+	unreadOnly, _ := strconv.ParseBool(r.URL.Query().Get("unread"))
+
+	notifications, err := h.notificationService.GetInbox(recipientID, unreadOnly, page, pageSize)
+
+	// Check and resolve errors from get inbox service
+	if err != nil {
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve inbox: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.NotificationResponse{
+		Code:    http.StatusOK,
+		Data:    notifications,
+		Message: "Inbox successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *NotificationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetUnreadCount")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	recipientID := int64(claims["id"].(float64))
+
+	count, err := h.notificationService.GetUnreadCount(recipientID)
+
+	// Check and resolve errors from get unread count service
+	if err != nil {
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to count unread notifications: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.NotificationResponse{
+		Code:    http.StatusOK,
+		Data:    map[string]int64{"unread": count},
+		Message: "Unread count successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *NotificationHandler) MarkNotificationAsRead(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: MarkNotificationAsRead")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	recipientID := int64(claims["id"].(float64))
+
+	err = h.notificationService.MarkNotificationAsRead(ID, recipientID)
+
+	// Check and resolve errors from mark notification as read service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: notification with id: %d was not found in your inbox", ID), http.StatusNotFound)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.NotificationResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Notification with ID: %d was marked as read", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *NotificationHandler) AcknowledgeNotification(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: AcknowledgeNotification")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	recipientID := int64(claims["id"].(float64))
+
+	err = h.notificationService.AcknowledgeNotification(ID, recipientID)
+
+	// Check and resolve errors from acknowledge notification service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: notification with id: %d was not found in your inbox", ID), http.StatusNotFound)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.NotificationResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Notification with ID: %d was acknowledged", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *NotificationHandler) MarkAllNotificationsAsRead(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: MarkAllNotificationsAsRead")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	recipientID := int64(claims["id"].(float64))
+
+	count, err := h.notificationService.MarkAllNotificationsAsRead(recipientID)
+
+	// Check and resolve errors from mark all notifications as read service
+	if err != nil {
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.NotificationResponse{
+		Code:    http.StatusOK,
+		Data:    map[string]int64{"updated": count},
+		Message: "Notifications were marked as read",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
 func (h *NotificationHandler) GetAllNotifications(w http.ResponseWriter, r *http.Request) {
 	log.Println("Endpoint Hit: GetAllNotifications")
 
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
 	// Check the page query in the url, convert it to an integer, resolve errors
 	page, err := strconv.Atoi(r.URL.Query().Get("page"))
 	if err != nil || page < 1 {
@@ -170,7 +411,7 @@ func (h *NotificationHandler) GetAllNotifications(w http.ResponseWriter, r *http
 		pageSize = 10 // default page size
 	}
 
-	notifications, err := h.notificationService.GetAllNotifications(page, pageSize)
+	notifications, err := h.notificationService.GetAllNotifications(userID, page, pageSize)
 
 	// Check and resolve errors from get all notifications service
 	if err != nil {
@@ -236,6 +477,18 @@ func (h *NotificationHandler) UpdateNotificationByID(w http.ResponseWriter, r *h
 			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, utils.ErrInvalidLocale) || errors.Is(err, utils.ErrInvalidTranslations) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, utils.ErrInvalidExpiry) || errors.Is(err, utils.ErrInvalidSendAt) || errors.Is(err, utils.ErrUnknownNotificationField) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, utils.ErrNotificationDispatched) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusConflict)
+			return
+		}
 		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/services"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+type PasswordResetHandler struct {
+	passwordResetService *services.PasswordResetService
+}
+
+func NewPasswordResetHandler(passwordResetService *services.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		passwordResetService: passwordResetService,
+	}
+}
+
+func (h *PasswordResetHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: RequestPasswordReset")
+
+	var resetRequest models.PasswordResetRequest
+
+	// Check and resolve errors during JSON decoding process
+	err := json.NewDecoder(r.Body).Decode(&resetRequest)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.passwordResetService.RequestPasswordReset(resetRequest.Email)
+
+	// Check and resolve errors from request password reset service
+	if err != nil {
+		if errors.Is(err, utils.ErrPasswordResetUnavailable) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusServiceUnavailable)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to request password reset: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	// The response is the same for unknown addresses, so it does not reveal who has an account
+	response := models.AuthResponse{
+		Code:    http.StatusAccepted,
+		Message: "If an account exists for this email address, a password reset token was sent to it",
+	}
+
+	// Write response header
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *PasswordResetHandler) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: ConfirmPasswordReset")
+
+	var confirmation models.PasswordResetConfirmation
+
+	// Check and resolve errors during JSON decoding process
+	err := json.NewDecoder(r.Body).Decode(&confirmation)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.passwordResetService.ConfirmPasswordReset(&confirmation)
+
+	// Check and resolve errors from confirm password reset service
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidPasswordResetToken) || isPasswordPolicyError(err) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to reset password: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AuthResponse{
+		Code:    http.StatusOK,
+		Message: "Password was successfully reset",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
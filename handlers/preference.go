@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/services"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+type PreferenceHandler struct {
+	preferenceService *services.PreferenceService
+}
+
+func NewPreferenceHandler(preferenceService *services.PreferenceService) *PreferenceHandler {
+	return &PreferenceHandler{
+		preferenceService: preferenceService,
+	}
+}
+
+// isPreferenceValidationError reports whether err was caused by invalid preferences.
+func isPreferenceValidationError(err error) bool {
+	return errors.Is(err, utils.ErrInvalidPriorityLabel) ||
+		errors.Is(err, utils.ErrInvalidTopicName) ||
+		errors.Is(err, utils.ErrUnknownChannel)
+}
+
+func (h *PreferenceHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetPreferences")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	claimsID := int64(claims["id"].(float64))
+
+	preferences, err := h.preferenceService.GetPreferences(ID, claimsID)
+
+	// Check and resolve errors from get preferences service
+	if err != nil {
+		if errors.Is(err, utils.ErrForbidden) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve preferences: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.PreferenceResponse{
+		Code:    http.StatusOK,
+		Data:    preferences,
+		Message: "Preferences successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *PreferenceHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: UpdatePreferences")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	claimsID := int64(claims["id"].(float64))
+
+	var preferences models.Preferences
+
+	// Check and resolve errors during JSON decoding process
+	err = json.NewDecoder(r.Body).Decode(&preferences)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	// Check and resolve errors from update preferences service
+	err = h.preferenceService.UpdatePreferences(ID, claimsID, &preferences)
+	if err != nil {
+		if errors.Is(err, utils.ErrForbidden) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		if isPreferenceValidationError(err) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to update preferences: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.PreferenceResponse{
+		Code:    http.StatusOK,
+		Message: "Preferences were successfully updated",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/services"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+type RecurrenceHandler struct {
+	recurrenceService *services.RecurrenceService
+}
+
+func NewRecurrenceHandler(recurrenceService *services.RecurrenceService) *RecurrenceHandler {
+	return &RecurrenceHandler{
+		recurrenceService: recurrenceService,
+	}
+}
+
+// isRecurrenceValidationError reports whether err was caused by an invalid recurrence definition.
+func isRecurrenceValidationError(err error) bool {
+	return errors.Is(err, utils.ErrInvalidRangeForPriority) ||
+		errors.Is(err, utils.ErrInvalidTopicName) ||
+		errors.Is(err, utils.ErrInvalidCronExpression) ||
+		errors.Is(err, utils.ErrInvalidTimezone) ||
+		errors.Is(err, utils.ErrInvalidCatchUpPolicy)
+}
+
+func (h *RecurrenceHandler) CreateRecurrence(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: CreateRecurrence")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	publisherID := int64(claims["id"].(float64))
+
+	var recurrenceInput models.RecurrenceInput
+
+	// Check and resolve errors during JSON decoding process
+	err = json.NewDecoder(r.Body).Decode(&recurrenceInput)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	// Check and resolve errors from the create recurrence service
+	err = h.recurrenceService.CreateRecurrence(&recurrenceInput, publisherID)
+	if err != nil {
+		if isRecurrenceValidationError(err) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, utils.ErrEmailNotVerified) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to create recurrence: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.RecurrenceResponse{
+		Code:    http.StatusCreated,
+		Message: "Recurrence was successfully created",
+	}
+
+	// Write response header
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *RecurrenceHandler) GetRecurrenceByID(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetRecurrenceByID")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid recurrence ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	publisherID := int64(claims["id"].(float64))
+
+	recurrence, err := h.recurrenceService.GetRecurrenceByID(ID, publisherID)
+
+	// Check and resolve errors from get recurrence by id service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: recurrence with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrForbidden) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve recurrence: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.RecurrenceResponse{
+		Code:    http.StatusOK,
+		Data:    recurrence,
+		Message: fmt.Sprintf("Recurrence with ID: %d was successfully retrieved", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *RecurrenceHandler) GetOwnRecurrences(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetOwnRecurrences")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	publisherID := int64(claims["id"].(float64))
+
+	// Check the page query in the url, convert it to an integer, resolve errors
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Check the pageSize query in the url, convert it to an integer, resolve errors
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10 // default page size
+	}
+
+	recurrences, err := h.recurrenceService.GetOwnRecurrences(publisherID, page, pageSize)
+
+	// Check and resolve errors from get own recurrences service
+	if err != nil {
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve recurrences: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.RecurrenceResponse{
+		Code:    http.StatusOK,
+		Data:    recurrences,
+		Message: "Recurrences successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *RecurrenceHandler) UpdateRecurrenceByID(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: UpdateRecurrenceByID")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid recurrence ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	publisherID := int64(claims["id"].(float64))
+
+	var recurrenceInput models.RecurrenceInput
+
+	// Check and resolve errors during JSON decoding process
+	err = json.NewDecoder(r.Body).Decode(&recurrenceInput)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.recurrenceService.UpdateRecurrenceByID(ID, publisherID, &recurrenceInput)
+
+	// Check and resolve errors from update recurrence by id service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: recurrence with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrForbidden) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		if isRecurrenceValidationError(err) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.RecurrenceResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Recurrence with ID: %d was successfully updated", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *RecurrenceHandler) DeleteRecurrenceByID(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: DeleteRecurrenceByID")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid recurrence ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	publisherID := int64(claims["id"].(float64))
+
+	err = h.recurrenceService.DeleteRecurrenceByID(ID, publisherID)
+
+	// Check and resolve errors from delete recurrence by id service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: recurrence with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrForbidden) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.RecurrenceResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Recurrence with ID: %d was successfully deleted", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
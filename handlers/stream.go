@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// streamHeartbeatInterval keeps idle streams from being closed by proxies.
+const streamHeartbeatInterval = 30 * time.Second
+
+func (h *NotificationHandler) StreamNotifications(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: StreamNotifications")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	recipientID := int64(claims["id"].(float64))
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		utils.RespondWithError(w, "Error: streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Browsers resend the last received event ID in a header, other clients may use the query string
+	lastEventIDValue := r.Header.Get("Last-Event-ID")
+	if lastEventIDValue == "" {
+		lastEventIDValue = r.URL.Query().Get("lastEventId")
+	}
+	var lastEventID int64
+	if lastEventIDValue != "" {
+		lastEventID, err = strconv.ParseInt(lastEventIDValue, 10, 64)
+		if err != nil || lastEventID < 0 {
+			utils.RespondWithError(w, "Error: invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Register before replaying so nothing created in between is lost
+	client := h.notificationService.Subscribe(recipientID)
+	if client == nil {
+		utils.RespondWithError(w, "Error: server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer h.notificationService.Unsubscribe(client)
+
+	var missed []*models.Notification
+	if lastEventIDValue != "" {
+		missed, err = h.notificationService.GetMissedNotifications(recipientID, lastEventID)
+		if err != nil {
+			utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve missed notifications: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	// Remember what was replayed so live events created in between are not sent twice
+	replayed := make(map[int64]struct{}, len(missed))
+	for _, notification := range missed {
+		if err := writeNotificationEvent(w, notification); err != nil {
+			return
+		}
+		replayed[notification.ID] = struct{}{}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case notification, ok := <-client.Send:
+			if !ok {
+				return
+			}
+			// Skip notifications that were already sent during replay
+			if _, ok := replayed[notification.ID]; ok {
+				continue
+			}
+			if err := writeNotificationEvent(w, notification.Localize(client.Locale)); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeNotificationEvent writes a notification as a server-sent event using its ID as the event ID.
+func writeNotificationEvent(w http.ResponseWriter, notification *models.Notification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", notification.ID, data)
+	return err
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/services"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+type TemplateHandler struct {
+	templateService *services.TemplateService
+}
+
+func NewTemplateHandler(templateService *services.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// isTemplateValidationError reports whether err was caused by an invalid template definition.
+func isTemplateValidationError(err error) bool {
+	return errors.Is(err, utils.ErrInvalidTemplateName) ||
+		errors.Is(err, utils.ErrInvalidTemplate) ||
+		errors.Is(err, utils.ErrInvalidLocale)
+}
+
+// isTemplateRenderError reports whether err was caused by a notification that could not be rendered
+// from its template.
+func isTemplateRenderError(err error) bool {
+	return errors.Is(err, utils.ErrTemplateNotFound) ||
+		errors.Is(err, utils.ErrTemplateConflict) ||
+		errors.Is(err, utils.ErrMissingTemplateVariable) ||
+		errors.Is(err, utils.ErrInvalidTemplateVariable) ||
+		errors.Is(err, utils.ErrTemplateTooLarge) ||
+		errors.Is(err, utils.ErrInvalidTemplate)
+}
+
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: CreateTemplate")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	publisherID := int64(claims["id"].(float64))
+
+	var templateInput models.TemplateInput
+
+	// Check and resolve errors during JSON decoding process
+	err = json.NewDecoder(r.Body).Decode(&templateInput)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	// Check and resolve errors from the create template service
+	template, err := h.templateService.CreateTemplate(&templateInput, publisherID)
+	if err != nil {
+		if isTemplateValidationError(err) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, utils.ErrTemplateExists) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusConflict)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to create template: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TemplateResponse{
+		Code:    http.StatusCreated,
+		Data:    template,
+		Message: "Template was successfully created",
+	}
+
+	// Write response header
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TemplateHandler) GetTemplateByName(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetTemplateByName")
+
+	name := mux.Vars(r)["name"]
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	publisherID := int64(claims["id"].(float64))
+
+	template, err := h.templateService.GetTemplateByName(publisherID, name)
+
+	// Check and resolve errors from get template by name service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: template with name: %s was not found", name), http.StatusNotFound)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve template: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TemplateResponse{
+		Code:    http.StatusOK,
+		Data:    template,
+		Message: fmt.Sprintf("Template with name: %s was successfully retrieved", name),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TemplateHandler) GetOwnTemplates(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetOwnTemplates")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	publisherID := int64(claims["id"].(float64))
+
+	// Check the page query in the url, convert it to an integer, resolve errors
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Check the pageSize query in the url, convert it to an integer, resolve errors
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10 // default page size
+	}
+
+	templates, err := h.templateService.GetOwnTemplates(publisherID, page, pageSize)
+
+	// Check and resolve errors from get own templates service
+	if err != nil {
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve templates: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TemplateResponse{
+		Code:    http.StatusOK,
+		Data:    templates,
+		Message: "Templates successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TemplateHandler) UpdateTemplateByName(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: UpdateTemplateByName")
+
+	name := mux.Vars(r)["name"]
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	publisherID := int64(claims["id"].(float64))
+
+	var templateInput models.TemplateInput
+
+	// Check and resolve errors during JSON decoding process
+	err = json.NewDecoder(r.Body).Decode(&templateInput)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.templateService.UpdateTemplateByName(publisherID, name, &templateInput)
+
+	// Check and resolve errors from update template by name service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: template with name: %s was not found", name), http.StatusNotFound)
+			return
+		}
+		if isTemplateValidationError(err) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, utils.ErrTemplateExists) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusConflict)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TemplateResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Template with name: %s was successfully updated", name),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TemplateHandler) DeleteTemplateByName(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: DeleteTemplateByName")
+
+	name := mux.Vars(r)["name"]
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	publisherID := int64(claims["id"].(float64))
+
+	err = h.templateService.DeleteTemplateByName(publisherID, name)
+
+	// Check and resolve errors from delete template by name service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: template with name: %s was not found", name), http.StatusNotFound)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TemplateResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Template with name: %s was successfully deleted", name),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akinolaemmanuel49/notify-api/templating"
+)
+
+// Notifications whose template cannot be rendered are rejected with 400 Bad Request.
+func TestRenderErrorsAreTemplateRenderErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		variables map[string]interface{}
+	}{
+		{"missing variable", "Deploy of {{.service}}", nil},
+		{"too large", "{{.v}}{{.v}}", map[string]interface{}{"v": strings.Repeat("x", 40*1024)}},
+		{"loop", "{{range 10}}x{{end}}", nil},
+	}
+	for _, tt := range tests {
+		_, err := templating.Render(tt.text, tt.variables)
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		if !isTemplateRenderError(err) {
+			t.Errorf("%s: %v is not reported as a template render error", tt.name, err)
+		}
+	}
+	if isTemplateRenderError(errors.New("connection refused")) {
+		t.Error("unrelated errors must not be reported as template render errors")
+	}
+}
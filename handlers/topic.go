@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/services"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+type TopicHandler struct {
+	topicService *services.TopicService
+}
+
+func NewTopicHandler(topicService *services.TopicService) *TopicHandler {
+	return &TopicHandler{
+		topicService: topicService,
+	}
+}
+
+func (h *TopicHandler) CreateTopic(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: CreateTopic")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	creatorID := int64(claims["id"].(float64))
+
+	var topicInput models.TopicInput
+
+	// Check and resolve errors during JSON decoding process
+	err = json.NewDecoder(r.Body).Decode(&topicInput)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	// Check and resolve errors from the create topic service
+	err = h.topicService.CreateTopic(&topicInput, creatorID)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidTopicName) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, utils.ErrTopicExists) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusConflict)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to create topic: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TopicResponse{
+		Code:    http.StatusCreated,
+		Message: "Topic was successfully created",
+	}
+
+	// Write response header
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TopicHandler) GetTopicByName(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetTopicByName")
+
+	name := mux.Vars(r)["name"]
+
+	topic, err := h.topicService.GetTopicByName(name)
+
+	// Check and resolve errors from get topic by name service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: topic %q was not found", name), http.StatusNotFound)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve topic: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TopicResponse{
+		Code:    http.StatusOK,
+		Data:    topic,
+		Message: fmt.Sprintf("Topic %q was successfully retrieved", name),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TopicHandler) GetAllTopics(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetAllTopics")
+
+	// Check the page query in the url, convert it to an integer, resolve errors
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Check the pageSize query in the url, convert it to an integer, resolve errors
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10 // default page size
+	}
+
+	topics, err := h.topicService.GetAllTopics(page, pageSize)
+
+	// Check and resolve errors from get all topics service
+	if err != nil {
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve topics: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TopicResponse{
+		Code:    http.StatusOK,
+		Data:    topics,
+		Message: "Topics successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TopicHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: Subscribe")
+
+	name := mux.Vars(r)["name"]
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	err = h.topicService.Subscribe(name, userID)
+
+	// Check and resolve errors from subscribe service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: topic %q was not found", name), http.StatusNotFound)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to subscribe: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TopicResponse{
+		Code:    http.StatusCreated,
+		Message: fmt.Sprintf("Successfully subscribed to topic %q", name),
+	}
+
+	// Write response header
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TopicHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: Unsubscribe")
+
+	name := mux.Vars(r)["name"]
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	err = h.topicService.Unsubscribe(name, userID)
+
+	// Check and resolve errors from unsubscribe service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: you are not subscribed to topic %q", name), http.StatusNotFound)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to unsubscribe: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TopicResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Successfully unsubscribed from topic %q", name),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TopicHandler) GetOwnSubscriptions(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetOwnSubscriptions")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	subscriptions, err := h.topicService.GetSubscriptions(userID)
+
+	// Check and resolve errors from get subscriptions service
+	if err != nil {
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve subscriptions: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.TopicResponse{
+		Code:    http.StatusOK,
+		Data:    subscriptions,
+		Message: "Subscriptions successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
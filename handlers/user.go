@@ -17,14 +17,23 @@ import (
 
 type UserHandler struct {
 	userService *services.UserService
+	authService *services.AuthService
 }
 
-func NewUserHandler(userService *services.UserService) *UserHandler {
+func NewUserHandler(userService *services.UserService, authService *services.AuthService) *UserHandler {
 	return &UserHandler{
 		userService: userService,
+		authService: authService,
 	}
 }
 
+// isPasswordPolicyError reports whether err was caused by a password the password policy rejects.
+func isPasswordPolicyError(err error) bool {
+	return errors.Is(err, utils.ErrPasswordTooShort) ||
+		errors.Is(err, utils.ErrPasswordTooLong) ||
+		errors.Is(err, utils.ErrPasswordBreached)
+}
+
 func (h *UserHandler) UserHealthCheck(w http.ResponseWriter, r *http.Request) {
 	log.Println("Endpoint Hit: Users HealthCheck")
 
@@ -48,11 +57,15 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check and resolve errors from the create user service
-	err = h.userService.CreateUser(&userInputWithPassword)
+	user, err := h.userService.CreateUser(&userInputWithPassword)
 	if errors.Is(err, utils.ErrDuplicateKey) {
 		utils.RespondWithError(w, "Error: email address already in use", http.StatusConflict)
 		return
 	}
+	if errors.Is(err, utils.ErrInvalidTimezone) || isPasswordPolicyError(err) {
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
 		return
@@ -60,10 +73,12 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 
 	response := models.UserResponse{
 		Code:    http.StatusCreated,
-		Message: "User was successfully created",
+		Data:    user,
+		Message: fmt.Sprintf("User with ID: %d was successfully created", user.ID),
 	}
 
 	// Write response header
+	w.Header().Set("Location", fmt.Sprintf("/api/users/%d", user.ID))
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
 }
@@ -173,6 +188,10 @@ func (h *UserHandler) UpdateUserByID(w http.ResponseWriter, r *http.Request) {
 			utils.RespondWithError(w, fmt.Sprintf("Error: notification with ID: %d was not found", ID), http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, utils.ErrUnknownUserField) || errors.Is(err, utils.ErrInvalidTimezone) || errors.Is(err, utils.ErrInvalidLocale) || errors.Is(err, utils.ErrInvalidQuietHours) || errors.Is(err, utils.ErrInvalidDigestFrequency) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
 		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
 		return
 	}
@@ -186,6 +205,79 @@ func (h *UserHandler) UpdateUserByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: ChangePassword")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	claimsID := int64(claims["id"].(float64))
+
+	var passwordChange models.AuthPasswordChange
+
+	// Check and resolve errors during JSON decoding process
+	err = json.NewDecoder(r.Body).Decode(&passwordChange)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.userService.ChangePassword(ID, claimsID, &passwordChange)
+
+	// Check and resolve errors from change password service
+	if err != nil {
+		if errors.Is(err, utils.ErrForbidden) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: user with ID: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrIncorrectPassword) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusUnauthorized)
+			return
+		}
+		if isPasswordPolicyError(err) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to change password: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	// The token used for this request was revoked with the others, so the client gets a new session
+	tokenPair, err := h.authService.IssueTokens(ID)
+	if err != nil {
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to generate token: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.UserResponse{
+		Code:    http.StatusOK,
+		Data:    tokenPair,
+		Message: fmt.Sprintf("Password of user with ID: %d was successfully changed", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
 func (h *UserHandler) DeleteUserByID(w http.ResponseWriter, r *http.Request) {
 	log.Println("Endpoint Hit: DeleteUserByID")
 
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/services"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type VerificationHandler struct {
+	verificationService *services.VerificationService
+}
+
+func NewVerificationHandler(verificationService *services.VerificationService) *VerificationHandler {
+	return &VerificationHandler{
+		verificationService: verificationService,
+	}
+}
+
+func (h *VerificationHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: VerifyEmail")
+
+	var verification models.EmailVerification
+
+	// Check and resolve errors during JSON decoding process
+	err := json.NewDecoder(r.Body).Decode(&verification)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.verificationService.VerifyEmail(verification.Token)
+
+	// Check and resolve errors from verify email service
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidVerificationToken) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to verify email address: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AuthResponse{
+		Code:    http.StatusOK,
+		Message: "Email address was successfully verified",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *VerificationHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: ResendVerification")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	claimsID := int64(claims["id"].(float64))
+
+	err = h.verificationService.ResendVerification(claimsID)
+
+	// Check and resolve errors from resend verification service
+	if err != nil {
+		if errors.Is(err, utils.ErrEmailAlreadyVerified) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, utils.ErrVerificationUnavailable) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusServiceUnavailable)
+			return
+		}
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: user with ID: %d was not found", claimsID), http.StatusNotFound)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to send verification email: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.AuthResponse{
+		Code:    http.StatusAccepted,
+		Message: "Verification email was sent",
+	}
+
+	// Write response header
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
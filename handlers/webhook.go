@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/services"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// isWebhookValidationError reports whether err was caused by an invalid webhook definition.
+func isWebhookValidationError(err error) bool {
+	return errors.Is(err, utils.ErrInvalidWebhookURL) ||
+		errors.Is(err, utils.ErrWebhookURLNotPublic) ||
+		errors.Is(err, utils.ErrInvalidWebhookFormat) ||
+		errors.Is(err, utils.ErrInvalidTopicName) ||
+		errors.Is(err, utils.ErrTopicNotFound)
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: CreateWebhook")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	var webhookInput models.WebhookInput
+
+	// Check and resolve errors during JSON decoding process
+	err = json.NewDecoder(r.Body).Decode(&webhookInput)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	// Check and resolve errors from the create webhook service
+	webhook, err := h.webhookService.CreateWebhook(&webhookInput, userID)
+	if err != nil {
+		if isWebhookValidationError(err) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to create webhook: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.WebhookResponse{
+		Code:    http.StatusCreated,
+		Data:    webhook,
+		Message: "Webhook was successfully created, store its secret as it will not be shown again",
+	}
+
+	// Write response header
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookHandler) GetWebhookByID(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetWebhookByID")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	webhook, err := h.webhookService.GetWebhookByID(ID, userID)
+
+	// Check and resolve errors from get webhook by id service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: webhook with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrForbidden) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve webhook: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.WebhookResponse{
+		Code:    http.StatusOK,
+		Data:    webhook,
+		Message: fmt.Sprintf("Webhook with ID: %d was successfully retrieved", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookHandler) GetOwnWebhooks(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetOwnWebhooks")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	// Check the page query in the url, convert it to an integer, resolve errors
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Check the pageSize query in the url, convert it to an integer, resolve errors
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10 // default page size
+	}
+
+	webhooks, err := h.webhookService.GetOwnWebhooks(userID, page, pageSize)
+
+	// Check and resolve errors from get own webhooks service
+	if err != nil {
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve webhooks: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.WebhookResponse{
+		Code:    http.StatusOK,
+		Data:    webhooks,
+		Message: "Webhooks successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookHandler) UpdateWebhookByID(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: UpdateWebhookByID")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	var webhookInput models.WebhookInput
+
+	// Check and resolve errors during JSON decoding process
+	err = json.NewDecoder(r.Body).Decode(&webhookInput)
+	if err != nil {
+		utils.RespondWithError(w, "Error: failed to parse request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.webhookService.UpdateWebhookByID(ID, userID, &webhookInput)
+
+	// Check and resolve errors from update webhook by id service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: webhook with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrForbidden) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		if isWebhookValidationError(err) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.WebhookResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Webhook with ID: %d was successfully updated", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookHandler) DeleteWebhookByID(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: DeleteWebhookByID")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	err = h.webhookService.DeleteWebhookByID(ID, userID)
+
+	// Check and resolve errors from delete webhook by id service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: webhook with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrForbidden) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.WebhookResponse{
+		Code:    http.StatusOK,
+		Message: fmt.Sprintf("Webhook with ID: %d was successfully deleted", ID),
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookHandler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: GetWebhookDeliveries")
+
+	vars := mux.Vars(r)
+
+	// Convert string to integer
+	ID, err := strconv.ParseInt(vars["id"], 10, 64)
+
+	// Check and resolve errors arising from string conversion
+	if err != nil {
+		utils.RespondWithError(w, "Error: invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	userID := int64(claims["id"].(float64))
+
+	// Check the page query in the url, convert it to an integer, resolve errors
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Check the pageSize query in the url, convert it to an integer, resolve errors
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10 // default page size
+	}
+
+	deliveries, err := h.webhookService.GetWebhookDeliveries(ID, userID, page, pageSize)
+
+	// Check and resolve errors from get webhook deliveries service
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: webhook with id: %d was not found", ID), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, utils.ErrForbidden) {
+			utils.RespondWithError(w, fmt.Sprintf("Error: %s", err.Error()), http.StatusForbidden)
+			return
+		}
+		utils.RespondWithError(w, fmt.Sprintf("Error: failed to retrieve webhook deliveries: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.WebhookResponse{
+		Code:    http.StatusOK,
+		Data:    deliveries,
+		Message: "Webhook deliveries successfully retrieved.",
+	}
+
+	// Encode and write JSON response
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/realtime"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Time allowed to write a frame to the peer.
+	socketWriteWait = 10 * time.Second
+	// Time allowed to read the next pong from the peer.
+	socketPongWait = 60 * time.Second
+	// Pings are sent with this period, which must be less than socketPongWait.
+	socketPingPeriod = (socketPongWait * 9) / 10
+	// Maximum size of a frame sent by the peer.
+	socketMaxMessageSize = 4096
+	// Number of replies to client frames that may be pending.
+	socketReplyBufferSize = 8
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Connections are authenticated with a bearer token rather than cookies,
+	// so cross-origin clients are allowed.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func (h *NotificationHandler) NotificationSocket(w http.ResponseWriter, r *http.Request) {
+	log.Println("Endpoint Hit: NotificationSocket")
+
+	// Extract user ID from JWT token
+	token, err := utils.GetToken(r)
+	if err != nil {
+		utils.RespondWithError(w, "Error: unauthorized access", http.StatusUnauthorized)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	recipientID := int64(claims["id"].(float64))
+
+	client := h.notificationService.Subscribe(recipientID)
+	if client == nil {
+		utils.RespondWithError(w, "Error: server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer h.notificationService.Unsubscribe(client)
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already written an error response
+		log.Println("Error upgrading connection:", err)
+		return
+	}
+
+	replies := make(chan models.RealtimeFrame, socketReplyBufferSize)
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		h.writeSocketFrames(conn, client, replies)
+	}()
+
+	h.readSocketFrames(conn, client, replies, writerDone)
+
+	// Stop deliveries and wait for the writer to drain what is already buffered
+	client.Close()
+	<-writerDone
+}
+
+// readSocketFrames handles frames sent by the client until the connection fails or is closed.
+func (h *NotificationHandler) readSocketFrames(conn *websocket.Conn, client *realtime.Client, replies chan<- models.RealtimeFrame, writerDone <-chan struct{}) {
+	conn.SetReadLimit(socketMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(socketPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(socketPongWait))
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Println("Error reading from socket:", err)
+			}
+			return
+		}
+
+		var reply models.RealtimeFrame
+		var frame models.RealtimeFrame
+		if err := json.Unmarshal(message, &frame); err != nil {
+			// Malformed frames are reported to the client without dropping the connection
+			reply = models.RealtimeFrame{Type: models.FrameError, Error: "frame must be a JSON object"}
+		} else {
+			reply = h.handleSocketFrame(client, &frame)
+		}
+		if !sendReply(replies, reply, writerDone) {
+			return
+		}
+	}
+}
+
+// handleSocketFrame applies a single client frame and returns the reply for it.
+func (h *NotificationHandler) handleSocketFrame(client *realtime.Client, frame *models.RealtimeFrame) models.RealtimeFrame {
+	switch frame.Type {
+	case models.FrameSubscribe:
+		switch {
+		case frame.Topic != "":
+			if err := h.notificationService.FollowTopic(client, frame.Topic); err != nil {
+				return models.RealtimeFrame{Type: models.FrameError, Topic: frame.Topic, Error: err.Error()}
+			}
+			return models.RealtimeFrame{Type: models.FrameSubscribed, Topic: frame.Topic}
+		case frame.PublisherID > 0:
+			h.notificationService.FollowPublisher(client, frame.PublisherID)
+			return models.RealtimeFrame{Type: models.FrameSubscribed, PublisherID: frame.PublisherID}
+		}
+		return models.RealtimeFrame{Type: models.FrameError, Error: "topic or publisher_id is required"}
+	case models.FrameUnsubscribe:
+		switch {
+		case frame.Topic != "":
+			h.notificationService.UnfollowTopic(client, frame.Topic)
+			return models.RealtimeFrame{Type: models.FrameUnsubscribed, Topic: frame.Topic}
+		case frame.PublisherID > 0:
+			h.notificationService.UnfollowPublisher(client, frame.PublisherID)
+			return models.RealtimeFrame{Type: models.FrameUnsubscribed, PublisherID: frame.PublisherID}
+		}
+		return models.RealtimeFrame{Type: models.FrameError, Error: "topic or publisher_id is required"}
+	case models.FrameAck, models.FrameRead:
+		if frame.NotificationID <= 0 {
+			return models.RealtimeFrame{Type: models.FrameError, Error: "notification_id is required"}
+		}
+		var err error
+		if frame.Type == models.FrameAck {
+			err = h.notificationService.AcknowledgeNotification(frame.NotificationID, client.UserID)
+		} else {
+			err = h.notificationService.MarkNotificationAsRead(frame.NotificationID, client.UserID)
+		}
+		if err != nil {
+			if errors.Is(err, utils.ErrNotFound) {
+				return models.RealtimeFrame{Type: models.FrameError, NotificationID: frame.NotificationID, Error: "notification was not found in your inbox"}
+			}
+			return models.RealtimeFrame{Type: models.FrameError, NotificationID: frame.NotificationID, Error: "failed to update notification state"}
+		}
+		return models.RealtimeFrame{Type: models.FrameAcked, NotificationID: frame.NotificationID}
+	}
+	return models.RealtimeFrame{Type: models.FrameError, Error: "unknown frame type"}
+}
+
+// writeSocketFrames writes notifications, replies and pings to the client. It returns once the
+// client's Send channel has been closed and drained, or a write fails.
+func (h *NotificationHandler) writeSocketFrames(conn *websocket.Conn, client *realtime.Client, replies <-chan models.RealtimeFrame) {
+	ticker := time.NewTicker(socketPingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case notification, ok := <-client.Send:
+			conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			if !ok {
+				// The client was closed or the server is shutting down
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+			frame := models.RealtimeFrame{Type: models.FrameNotification, Data: notification.Localize(client.Locale)}
+			if err := conn.WriteJSON(frame); err != nil {
+				return
+			}
+		case reply := <-replies:
+			conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			if err := conn.WriteJSON(reply); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendReply queues a reply for the writer. It returns false once the writer has stopped.
+func sendReply(replies chan<- models.RealtimeFrame, reply models.RealtimeFrame, writerDone <-chan struct{}) bool {
+	select {
+	case replies <- reply:
+		return true
+	case <-writerDone:
+		return false
+	}
+}
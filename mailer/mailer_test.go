@@ -0,0 +1,291 @@
+package mailer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// session records what a fakeServer received over one connection.
+type session struct {
+	auth     string
+	mailFrom string
+	rcptTo   []string
+	data     []byte
+	commands []string
+}
+
+// fakeServer is an in-process SMTP server that accepts a single connection.
+type fakeServer struct {
+	listener   net.Listener
+	extensions []string
+	// certificate is presented when a client issues STARTTLS.
+	certificate *tls.Certificate
+	done        chan *session
+}
+
+// newFakeServer starts a server that advertises extensions and, when certificate is not nil,
+// implements STARTTLS with it.
+func newFakeServer(t *testing.T, certificate *tls.Certificate, extensions ...string) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	server := &fakeServer{listener: listener, extensions: extensions, certificate: certificate, done: make(chan *session, 1)}
+	go server.serve()
+	return server
+}
+
+func (s *fakeServer) config() Config {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return Config{Host: "127.0.0.1", Port: addr.Port, From: "Notify <notify@example.com>"}
+}
+
+func (s *fakeServer) serve() {
+	record := &session{}
+	defer func() { s.done <- record }()
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		record.commands = append(record.commands, line)
+		verb, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			lines := append([]string{"localhost"}, s.extensions...)
+			for i, extension := range lines {
+				separator := "-"
+				if i == len(lines)-1 {
+					separator = " "
+				}
+				text.PrintfLine("250%s%s", separator, extension)
+			}
+		case "AUTH":
+			mechanism, credentials, _ := strings.Cut(argument, " ")
+			decoded, err := base64.StdEncoding.DecodeString(credentials)
+			if mechanism != "PLAIN" || err != nil {
+				text.PrintfLine("504 unsupported")
+				continue
+			}
+			record.auth = string(decoded)
+			text.PrintfLine("235 authenticated")
+		case "MAIL":
+			record.mailFrom = argument
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			record.rcptTo = append(record.rcptTo, argument)
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			record.data, err = text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			text.PrintfLine("250 queued")
+		case "STARTTLS":
+			if s.certificate == nil {
+				text.PrintfLine("502 not implemented")
+				continue
+			}
+			text.PrintfLine("220 ready to start TLS")
+			tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{*s.certificate}})
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			text = textproto.NewConn(tlsConn)
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	server := newFakeServer(t, nil, "8BITMIME", "AUTH PLAIN")
+	config := server.config()
+	config.Username = "notify"
+	config.Password = "s3cret"
+
+	err := New(config).Send(&Message{
+		To:      "Jane Doe <jane@example.com>",
+		Subject: "Your digest: 3 notifications\r\nBcc: attacker@example.com",
+		Text:    "Hello Jane, café",
+		HTML:    "<p>Hello Jane, café</p>",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	record := <-server.done
+
+	if record.auth != "\x00notify\x00s3cret" {
+		t.Errorf("AUTH PLAIN credentials = %q", record.auth)
+	}
+	if record.mailFrom != "FROM:<notify@example.com>" && !strings.HasPrefix(record.mailFrom, "FROM:<notify@example.com> ") {
+		t.Errorf("MAIL %s, want FROM:<notify@example.com>", record.mailFrom)
+	}
+	if len(record.rcptTo) != 1 || record.rcptTo[0] != "TO:<jane@example.com>" {
+		t.Errorf("RCPT %v, want TO:<jane@example.com>", record.rcptTo)
+	}
+
+	message, err := mail.ReadMessage(strings.NewReader(string(record.data)))
+	if err != nil {
+		t.Fatalf("parsing message: %v", err)
+	}
+	if message.Header.Get("Bcc") != "" {
+		t.Error("subject injected a Bcc header")
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if err != nil || subject != "Your digest: 3 notifications\r\nBcc: attacker@example.com" {
+		t.Errorf("Subject = %q (%v)", subject, err)
+	}
+	if to := message.Header.Get("To"); to != `"Jane Doe" <jane@example.com>` {
+		t.Errorf("To = %q", to)
+	}
+	if message.Header.Get("Date") == "" || !strings.HasSuffix(message.Header.Get("Message-ID"), "@example.com>") {
+		t.Errorf("Date = %q, Message-ID = %q", message.Header.Get("Date"), message.Header.Get("Message-ID"))
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q (%v), want multipart/alternative", message.Header.Get("Content-Type"), err)
+	}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	wantParts := []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", "Hello Jane, café"},
+		{"text/html; charset=utf-8", "<p>Hello Jane, café</p>"},
+	}
+	for _, want := range wantParts {
+		part, err := reader.NextRawPart()
+		if err != nil {
+			t.Fatalf("reading %s part: %v", want.contentType, err)
+		}
+		if part.Header.Get("Content-Type") != want.contentType || part.Header.Get("Content-Transfer-Encoding") != "quoted-printable" {
+			t.Errorf("part headers = %v", part.Header)
+		}
+		content, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil || string(content) != want.content {
+			t.Errorf("%s part = %q (%v), want %q", want.contentType, content, err, want.content)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("expected exactly two parts, got error %v", err)
+	}
+}
+
+func TestSendWithoutAuthentication(t *testing.T) {
+	server := newFakeServer(t, nil, "AUTH PLAIN")
+
+	if err := New(server.config()).Send(&Message{To: "jane@example.com", Subject: "Hi", Text: "Hi", HTML: "<p>Hi</p>"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	record := <-server.done
+	for _, command := range record.commands {
+		if strings.HasPrefix(command, "AUTH") {
+			t.Errorf("authenticated without a username: %q", command)
+		}
+	}
+	if len(record.data) == 0 {
+		t.Error("no message was sent")
+	}
+}
+
+func TestSendRequiresStartTLS(t *testing.T) {
+	server := newFakeServer(t, nil, "AUTH PLAIN")
+	config := server.config()
+	config.StartTLS = true
+	config.Username = "notify"
+	config.Password = "s3cret"
+
+	err := New(config).Send(&Message{To: "jane@example.com", Subject: "Hi", Text: "Hi", HTML: "<p>Hi</p>"})
+	if !errors.Is(err, ErrStartTLSUnsupported) {
+		t.Fatalf("got error %v, want ErrStartTLSUnsupported", err)
+	}
+	record := <-server.done
+	// Nothing, least of all the credentials, may be sent in the clear
+	for _, command := range record.commands {
+		if verb, _, _ := strings.Cut(command, " "); verb != "EHLO" && verb != "HELO" && verb != "QUIT" {
+			t.Errorf("sent %q over an unencrypted connection", command)
+		}
+	}
+}
+
+// selfSignedCertificate returns a certificate for 127.0.0.1 that no client trusts.
+func selfSignedCertificate(t *testing.T) *tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestSendStartTLSVerifiesCertificate(t *testing.T) {
+	server := newFakeServer(t, selfSignedCertificate(t), "STARTTLS", "AUTH PLAIN")
+	config := server.config()
+	config.StartTLS = true
+	config.Username = "notify"
+	config.Password = "s3cret"
+
+	var certificateErr *tls.CertificateVerificationError
+	err := New(config).Send(&Message{To: "jane@example.com", Subject: "Hi", Text: "Hi", HTML: "<p>Hi</p>"})
+	if !errors.As(err, &certificateErr) {
+		t.Fatalf("got error %v, want a certificate verification error", err)
+	}
+	record := <-server.done
+	if len(record.commands) < 2 || record.commands[1] != "STARTTLS" {
+		t.Errorf("commands = %v, want STARTTLS right after EHLO", record.commands)
+	}
+	if record.auth != "" || record.mailFrom != "" {
+		t.Error("sent credentials or mail over an untrusted connection")
+	}
+}
+
+func TestSendRejectsInvalidAddresses(t *testing.T) {
+	config := Config{Host: "127.0.0.1", Port: 1, From: "not an address"}
+	if err := New(config).Send(&Message{To: "jane@example.com"}); err == nil {
+		t.Error("expected an invalid sender address to fail")
+	}
+	config.From = "notify@example.com"
+	if err := New(config).Send(&Message{To: "jane@"}); err == nil {
+		t.Error("expected an invalid recipient address to fail")
+	}
+}
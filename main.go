@@ -8,19 +8,28 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/akinolaemmanuel49/notify-api/channels"
 	"github.com/akinolaemmanuel49/notify-api/config"
 	"github.com/akinolaemmanuel49/notify-api/handlers"
+	"github.com/akinolaemmanuel49/notify-api/mailer"
 	"github.com/akinolaemmanuel49/notify-api/middlewares"
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/passwords"
+	"github.com/akinolaemmanuel49/notify-api/realtime"
 	"github.com/akinolaemmanuel49/notify-api/repositories"
 	"github.com/akinolaemmanuel49/notify-api/services"
 	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/akinolaemmanuel49/notify-api/workers"
 	"github.com/gorilla/mux"
 	_ "github.com/lib/pq"
 )
 
-func handleRequests(notificationHandler *handlers.NotificationHandler, userHandler *handlers.UserHandler, authHandler *handlers.AuthHandler) {
+func handleRequests(notificationHandler *handlers.NotificationHandler, userHandler *handlers.UserHandler, authHandler *handlers.AuthHandler, topicHandler *handlers.TopicHandler, recurrenceHandler *handlers.RecurrenceHandler, webhookHandler *handlers.WebhookHandler, adminHandler *handlers.AdminHandler, channelHandler *handlers.ChannelHandler, preferenceHandler *handlers.PreferenceHandler, templateHandler *handlers.TemplateHandler, verificationHandler *handlers.VerificationHandler, passwordResetHandler *handlers.PasswordResetHandler, hub *realtime.Hub) {
 	// Define HTTP router
 	router := mux.NewRouter().StrictSlash(true)
 	apiRouter := router.PathPrefix("/api").Subrouter()
@@ -30,11 +39,23 @@ func handleRequests(notificationHandler *handlers.NotificationHandler, userHandl
 	handleNotificationRequests(apiRouter, notificationHandler)
 	handleUserRequests(apiRouter, userHandler)
 	handleAuthRequest(apiRouter, authHandler)
+	handleTopicRequests(apiRouter, topicHandler)
+	handleRecurrenceRequests(apiRouter, recurrenceHandler)
+	handleWebhookRequests(apiRouter, webhookHandler)
+	handleAdminRequests(apiRouter, adminHandler)
+	handleChannelRequests(apiRouter, channelHandler)
+	handlePreferenceRequests(apiRouter, preferenceHandler)
+	handleTemplateRequests(apiRouter, templateHandler)
+	handleVerificationRequests(apiRouter, verificationHandler)
+	handlePasswordResetRequests(apiRouter, passwordResetHandler)
+	handleWellKnownRequests(router, authHandler)
 
 	server := &http.Server{
 		Addr:    ":8080",
 		Handler: router,
 	}
+	// Disconnect live notification streams so shutdown does not wait on them
+	server.RegisterOnShutdown(hub.Close)
 
 	// Start the server in a separate goroutine
 	go func() {
@@ -56,6 +77,10 @@ func handleRequests(notificationHandler *handlers.NotificationHandler, userHandl
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("Server shutdown failed: %v", err)
 	}
+	// Hijacked WebSocket connections are not tracked by the server, wait for them separately
+	if err := hub.Wait(ctx); err != nil {
+		log.Println("Live connections did not drain in time:", err)
+	}
 
 	log.Println("Server gracefully stopped")
 }
@@ -65,10 +90,17 @@ func handleNotificationRequests(apiRouter *mux.Router, notificationHandler *hand
 	apiRouter.HandleFunc("/notifications/healthCheck", notificationHandler.NotificationHealthCheck).Methods("GET")
 	apiRouter.HandleFunc("/notifications", middlewares.JWTAuthMiddleware(notificationHandler.CreateNotification)).Methods("POST")
 	apiRouter.HandleFunc("/notifications/me", middlewares.JWTAuthMiddleware(notificationHandler.GetOwnNotifications)).Methods("GET")
-	apiRouter.HandleFunc("/notifications", notificationHandler.GetAllNotifications).Methods("GET")
-	apiRouter.HandleFunc("/notifications/{id}", notificationHandler.GetNotificationByID).Methods("GET")
+	apiRouter.HandleFunc("/notifications/stream", middlewares.JWTAuthMiddleware(notificationHandler.StreamNotifications)).Methods("GET")
+	apiRouter.HandleFunc("/notifications/ws", middlewares.JWTAuthMiddleware(notificationHandler.NotificationSocket)).Methods("GET")
+	apiRouter.HandleFunc("/notifications/read-all", middlewares.JWTAuthMiddleware(notificationHandler.MarkAllNotificationsAsRead)).Methods("POST")
+	apiRouter.HandleFunc("/notifications", middlewares.JWTAuthMiddleware(notificationHandler.GetAllNotifications)).Methods("GET")
+	apiRouter.HandleFunc("/notifications/{id}", middlewares.JWTAuthMiddleware(notificationHandler.GetNotificationByID)).Methods("GET")
 	apiRouter.HandleFunc("/notifications/{id}", middlewares.JWTAuthMiddleware(notificationHandler.UpdateNotificationByID)).Methods("PUT")
 	apiRouter.HandleFunc("/notifications/{id}", middlewares.JWTAuthMiddleware(notificationHandler.DeleteNotificationByID)).Methods("DELETE")
+	apiRouter.HandleFunc("/notifications/{id}/read", middlewares.JWTAuthMiddleware(notificationHandler.MarkNotificationAsRead)).Methods("POST")
+	apiRouter.HandleFunc("/notifications/{id}/acknowledge", middlewares.JWTAuthMiddleware(notificationHandler.AcknowledgeNotification)).Methods("POST")
+	apiRouter.HandleFunc("/inbox", middlewares.JWTAuthMiddleware(notificationHandler.GetInbox)).Methods("GET")
+	apiRouter.HandleFunc("/inbox/unread-count", middlewares.JWTAuthMiddleware(notificationHandler.GetUnreadCount)).Methods("GET")
 }
 
 func handleUserRequests(apiRouter *mux.Router, userHandler *handlers.UserHandler) {
@@ -79,11 +111,90 @@ func handleUserRequests(apiRouter *mux.Router, userHandler *handlers.UserHandler
 	apiRouter.HandleFunc("/users/{id}", userHandler.GetUserByID).Methods("GET")
 	apiRouter.HandleFunc("/users/{id}", middlewares.JWTAuthMiddleware(userHandler.UpdateUserByID)).Methods("PUT")
 	apiRouter.HandleFunc("/users/{id}", middlewares.JWTAuthMiddleware(userHandler.DeleteUserByID)).Methods("DELETE")
+	apiRouter.HandleFunc("/users/{id}/password", middlewares.JWTAuthMiddleware(userHandler.ChangePassword)).Methods("PUT")
 }
 
 func handleAuthRequest(apiRouter *mux.Router, authHandler *handlers.AuthHandler) {
 	// Auth
 	apiRouter.HandleFunc("/auth/token", authHandler.GenerateToken).Methods("POST")
+	apiRouter.HandleFunc("/auth/refresh", authHandler.RefreshToken).Methods("POST")
+	apiRouter.HandleFunc("/auth/logout", middlewares.JWTAuthMiddleware(authHandler.Logout)).Methods("POST")
+}
+
+func handleWellKnownRequests(router *mux.Router, authHandler *handlers.AuthHandler) {
+	// Well-known
+	router.HandleFunc("/.well-known/jwks.json", authHandler.GetJWKS).Methods("GET")
+}
+
+func handleTopicRequests(apiRouter *mux.Router, topicHandler *handlers.TopicHandler) {
+	// Topics
+	apiRouter.HandleFunc("/topics", middlewares.JWTAuthMiddleware(topicHandler.CreateTopic)).Methods("POST")
+	apiRouter.HandleFunc("/topics", topicHandler.GetAllTopics).Methods("GET")
+	apiRouter.HandleFunc("/topics/{name}", topicHandler.GetTopicByName).Methods("GET")
+	apiRouter.HandleFunc("/topics/{name}/subscriptions", middlewares.JWTAuthMiddleware(topicHandler.Subscribe)).Methods("POST")
+	apiRouter.HandleFunc("/topics/{name}/subscriptions", middlewares.JWTAuthMiddleware(topicHandler.Unsubscribe)).Methods("DELETE")
+	apiRouter.HandleFunc("/subscriptions/me", middlewares.JWTAuthMiddleware(topicHandler.GetOwnSubscriptions)).Methods("GET")
+}
+
+func handleRecurrenceRequests(apiRouter *mux.Router, recurrenceHandler *handlers.RecurrenceHandler) {
+	// Recurrences
+	apiRouter.HandleFunc("/recurrences", middlewares.JWTAuthMiddleware(recurrenceHandler.CreateRecurrence)).Methods("POST")
+	apiRouter.HandleFunc("/recurrences", middlewares.JWTAuthMiddleware(recurrenceHandler.GetOwnRecurrences)).Methods("GET")
+	apiRouter.HandleFunc("/recurrences/{id}", middlewares.JWTAuthMiddleware(recurrenceHandler.GetRecurrenceByID)).Methods("GET")
+	apiRouter.HandleFunc("/recurrences/{id}", middlewares.JWTAuthMiddleware(recurrenceHandler.UpdateRecurrenceByID)).Methods("PUT")
+	apiRouter.HandleFunc("/recurrences/{id}", middlewares.JWTAuthMiddleware(recurrenceHandler.DeleteRecurrenceByID)).Methods("DELETE")
+}
+
+func handleWebhookRequests(apiRouter *mux.Router, webhookHandler *handlers.WebhookHandler) {
+	// Webhooks
+	apiRouter.HandleFunc("/webhooks", middlewares.JWTAuthMiddleware(webhookHandler.CreateWebhook)).Methods("POST")
+	apiRouter.HandleFunc("/webhooks", middlewares.JWTAuthMiddleware(webhookHandler.GetOwnWebhooks)).Methods("GET")
+	apiRouter.HandleFunc("/webhooks/{id}", middlewares.JWTAuthMiddleware(webhookHandler.GetWebhookByID)).Methods("GET")
+	apiRouter.HandleFunc("/webhooks/{id}", middlewares.JWTAuthMiddleware(webhookHandler.UpdateWebhookByID)).Methods("PUT")
+	apiRouter.HandleFunc("/webhooks/{id}", middlewares.JWTAuthMiddleware(webhookHandler.DeleteWebhookByID)).Methods("DELETE")
+	apiRouter.HandleFunc("/webhooks/{id}/deliveries", middlewares.JWTAuthMiddleware(webhookHandler.GetWebhookDeliveries)).Methods("GET")
+}
+
+func handleAdminRequests(apiRouter *mux.Router, adminHandler *handlers.AdminHandler) {
+	// Admin
+	apiRouter.HandleFunc("/admin/delivery-jobs", middlewares.JWTAuthMiddleware(adminHandler.GetDeliveryJobs)).Methods("GET")
+	apiRouter.HandleFunc("/admin/dead-letters", middlewares.JWTAuthMiddleware(adminHandler.GetDeadLetterJobs)).Methods("GET")
+	apiRouter.HandleFunc("/admin/dead-letters/{id}", middlewares.JWTAuthMiddleware(adminHandler.GetDeadLetterJobByID)).Methods("GET")
+	apiRouter.HandleFunc("/admin/dead-letters/{id}", middlewares.JWTAuthMiddleware(adminHandler.DeleteDeadLetterJobByID)).Methods("DELETE")
+	apiRouter.HandleFunc("/admin/dead-letters/{id}/replay", middlewares.JWTAuthMiddleware(adminHandler.ReplayDeadLetterJob)).Methods("POST")
+	apiRouter.HandleFunc("/admin/users/{id}/verify-email", middlewares.JWTAuthMiddleware(adminHandler.VerifyUserEmail)).Methods("POST")
+}
+
+func handleChannelRequests(apiRouter *mux.Router, channelHandler *handlers.ChannelHandler) {
+	// Channels
+	apiRouter.HandleFunc("/channels", channelHandler.GetChannels).Methods("GET")
+}
+
+func handlePreferenceRequests(apiRouter *mux.Router, preferenceHandler *handlers.PreferenceHandler) {
+	// Preferences
+	apiRouter.HandleFunc("/users/{id}/preferences", middlewares.JWTAuthMiddleware(preferenceHandler.GetPreferences)).Methods("GET")
+	apiRouter.HandleFunc("/users/{id}/preferences", middlewares.JWTAuthMiddleware(preferenceHandler.UpdatePreferences)).Methods("PUT")
+}
+
+func handleTemplateRequests(apiRouter *mux.Router, templateHandler *handlers.TemplateHandler) {
+	// Templates
+	apiRouter.HandleFunc("/templates", middlewares.JWTAuthMiddleware(templateHandler.CreateTemplate)).Methods("POST")
+	apiRouter.HandleFunc("/templates", middlewares.JWTAuthMiddleware(templateHandler.GetOwnTemplates)).Methods("GET")
+	apiRouter.HandleFunc("/templates/{name}", middlewares.JWTAuthMiddleware(templateHandler.GetTemplateByName)).Methods("GET")
+	apiRouter.HandleFunc("/templates/{name}", middlewares.JWTAuthMiddleware(templateHandler.UpdateTemplateByName)).Methods("PUT")
+	apiRouter.HandleFunc("/templates/{name}", middlewares.JWTAuthMiddleware(templateHandler.DeleteTemplateByName)).Methods("DELETE")
+}
+
+func handleVerificationRequests(apiRouter *mux.Router, verificationHandler *handlers.VerificationHandler) {
+	// Email verification
+	apiRouter.HandleFunc("/auth/verify", verificationHandler.VerifyEmail).Methods("POST")
+	apiRouter.HandleFunc("/auth/verify/resend", middlewares.JWTAuthMiddleware(verificationHandler.ResendVerification)).Methods("POST")
+}
+
+func handlePasswordResetRequests(apiRouter *mux.Router, passwordResetHandler *handlers.PasswordResetHandler) {
+	// Password reset
+	apiRouter.HandleFunc("/auth/password-reset/request", passwordResetHandler.RequestPasswordReset).Methods("POST")
+	apiRouter.HandleFunc("/auth/password-reset/confirm", passwordResetHandler.ConfirmPasswordReset).Methods("POST")
 }
 
 func main() {
@@ -93,6 +204,10 @@ func main() {
 	cfg.ReadFile("dev-config.yml") // For use in development
 	cfg.ReadEnv()
 
+	if err := utils.LoadJWTKeys(cfg.JWT.Algorithm, cfg.JWT.Key, cfg.JWT.SigningKey, strings.Split(cfg.JWT.VerificationKeys, ",")); err != nil {
+		log.Fatalf("Could not load JWT keys: %v", err)
+	}
+
 	DATABASE_URI := fmt.Sprintf(
 		"postgres://%s:%s@localhost:5432/%s?sslmode=disable",
 		cfg.Database.User, cfg.Database.Pass, cfg.Database.Name)
@@ -106,17 +221,200 @@ func main() {
 	notificationRepository := repositories.NewNotificationRepository(db)
 	userRepository := repositories.NewUserRepository(db)
 	authRepository := repositories.NewAuthRepository(db)
+	topicRepository := repositories.NewTopicRepository(db)
+	recurrenceRepository := repositories.NewRecurrenceRepository(db)
+	webhookRepository := repositories.NewWebhookRepository(db)
+	deliveryRepository := repositories.NewDeliveryRepository(db)
+	preferenceRepository := repositories.NewPreferenceRepository(db)
+	digestRepository := repositories.NewDigestRepository(db)
+	templateRepository := repositories.NewTemplateRepository(db)
+	idempotencyRepository := repositories.NewIdempotencyRepository(db)
+	verificationRepository := repositories.NewVerificationRepository(db)
+	passwordResetRepository := repositories.NewPasswordResetRepository(db)
+
+	// Initialize live notification hub
+	hub := realtime.NewHub()
 
 	// Initialize services
-	notificationService := services.NewNotificationService(notificationRepository)
-	userService := services.NewUserService(userRepository)
-	authService := services.NewAuthService(authRepository)
+	maxDeliveryAttempts, err := strconv.Atoi(cfg.Delivery.MaxAttempts)
+	if err != nil || maxDeliveryAttempts < 1 {
+		maxDeliveryAttempts = 8 // Set a default value
+	}
+	channelRegistry := newChannelRegistry(&cfg, webhookRepository, userRepository)
+	deliveryService := services.NewDeliveryService(deliveryRepository, notificationRepository, userRepository, preferenceRepository, channelRegistry, maxDeliveryAttempts)
+	webhookService := services.NewWebhookService(webhookRepository)
+	preferenceService := services.NewPreferenceService(preferenceRepository, channelRegistry)
+	templateService := services.NewTemplateService(templateRepository)
+	idempotencyRetention, err := strconv.Atoi(cfg.Idempotency.Retention)
+	if err != nil {
+		idempotencyRetention = 24 // Set a default value (assuming retention is in hours)
+	}
+	idempotencyLease, err := strconv.Atoi(cfg.Idempotency.Lease)
+	if err != nil {
+		idempotencyLease = 60 // Set a default value (assuming lease is in seconds)
+	}
+	idempotencyService := services.NewIdempotencyService(idempotencyRepository, time.Hour*time.Duration(idempotencyRetention), time.Second*time.Duration(idempotencyLease))
+	// Digests, verification and password reset emails are sent through the email channel, when it is enabled
+	registeredEmailChannel, _ := channelRegistry.Get(models.ChannelEmail)
+	emailChannel, _ := registeredEmailChannel.(*channels.EmailChannel)
+	if emailChannel == nil {
+		log.Println("Error: email is not configured, new accounts cannot verify their email address and will not be able to publish notifications until an administrator verifies them")
+	}
+	digestService := services.NewDigestService(digestRepository, userRepository, emailChannel)
+	notificationService := services.NewNotificationService(notificationRepository, templateRepository, userRepository, hub, deliveryService)
+	verificationTokenTTL, err := strconv.Atoi(cfg.Verification.TokenTTL)
+	if err != nil {
+		verificationTokenTTL = 24 // Set a default value (assuming TTL is in hours)
+	}
+	verificationService := services.NewVerificationService(verificationRepository, userRepository, emailChannel, time.Hour*time.Duration(verificationTokenTTL))
+	passwordResetTokenTTL, err := strconv.Atoi(cfg.PasswordReset.TokenTTL)
+	if err != nil {
+		passwordResetTokenTTL = 60 // Set a default value (assuming TTL is in minutes)
+	}
+	passwordMinLength, err := strconv.Atoi(cfg.Password.MinLength)
+	if err != nil {
+		passwordMinLength = 8 // Set a default value
+	}
+	passwordPolicy, err := passwords.NewPolicy(passwordMinLength, cfg.Password.BreachedList)
+	if err != nil {
+		log.Fatalf("Could not load breached password list: %v", err)
+	}
+	passwordResetService := services.NewPasswordResetService(passwordResetRepository, userRepository, emailChannel, passwordPolicy, time.Minute*time.Duration(passwordResetTokenTTL))
+	userService := services.NewUserService(userRepository, verificationService, passwordPolicy)
+	refreshTokenTTL, err := strconv.Atoi(cfg.JWT.RefreshTokenTTL)
+	if err != nil {
+		refreshTokenTTL = 720 // Set a default value (assuming TTL is in hours)
+	}
+	authService := services.NewAuthService(authRepository, time.Hour*time.Duration(refreshTokenTTL))
+	// Reject logged out tokens, tokens of deleted users and tokens issued before a password change
+	middlewares.SetTokenRevocationChecker(authService)
+	topicService := services.NewTopicService(topicRepository)
+
+	catchUpPolicy := cfg.Scheduler.CatchUpPolicy
+	if models.ValidateCatchUpPolicy(catchUpPolicy) != nil {
+		catchUpPolicy = models.CatchUpOnce // Set a default value
+	}
+	recurrenceService := services.NewRecurrenceService(recurrenceRepository, notificationService, catchUpPolicy)
 
 	// Initialize handlers
-	notificationHandler := handlers.NewNotificationHandler(notificationService)
-	userHandler := handlers.NewUserHandler(userService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, idempotencyService)
+	userHandler := handlers.NewUserHandler(userService, authService)
 	authHandler := handlers.NewAuthHandler(authService)
+	topicHandler := handlers.NewTopicHandler(topicService)
+	recurrenceHandler := handlers.NewRecurrenceHandler(recurrenceService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	adminHandler := handlers.NewAdminHandler(deliveryService, verificationService)
+	channelHandler := handlers.NewChannelHandler(deliveryService)
+	preferenceHandler := handlers.NewPreferenceHandler(preferenceService)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	verificationHandler := handlers.NewVerificationHandler(verificationService)
+	passwordResetHandler := handlers.NewPasswordResetHandler(passwordResetService)
+
+	// Start background workers, they are stopped once the server has shut down
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+
+	schedulerInterval, err := strconv.Atoi(cfg.Scheduler.Interval)
+	if err != nil {
+		schedulerInterval = 10 // Set a default value (assuming interval is in seconds)
+	}
+	scheduler := workers.NewScheduler(notificationService, time.Second*time.Duration(schedulerInterval))
+	startWorker(ctx, &wg, scheduler.Run)
+	recurrenceGenerator := workers.NewRecurrenceGenerator(recurrenceService, time.Second*time.Duration(schedulerInterval))
+	startWorker(ctx, &wg, recurrenceGenerator.Run)
+
+	janitorInterval, err := strconv.Atoi(cfg.Janitor.Interval)
+	if err != nil {
+		janitorInterval = 300 // Set a default value (assuming interval is in seconds)
+	}
+	janitor := workers.NewJanitor(notificationService, idempotencyService, authService, time.Second*time.Duration(janitorInterval), cfg.Janitor.Mode == "archive")
+	startWorker(ctx, &wg, janitor.Run)
+
+	deliveryInterval, err := strconv.Atoi(cfg.Delivery.Interval)
+	if err != nil {
+		deliveryInterval = 5 // Set a default value (assuming interval is in seconds)
+	}
+	deliveryWorker := workers.NewDeliveryWorker(deliveryService, time.Second*time.Duration(deliveryInterval))
+	startWorker(ctx, &wg, deliveryWorker.Run)
+
+	quietHoursInterval, err := strconv.Atoi(cfg.QuietHours.Interval)
+	if err != nil {
+		quietHoursInterval = 60 // Set a default value (assuming interval is in seconds)
+	}
+	quietHoursWorker := workers.NewQuietHoursWorker(deliveryService, time.Second*time.Duration(quietHoursInterval))
+	startWorker(ctx, &wg, quietHoursWorker.Run)
+
+	digestInterval, err := strconv.Atoi(cfg.Digest.Interval)
+	if err != nil {
+		digestInterval = 60 // Set a default value (assuming interval is in seconds)
+	}
+	digestWorker := workers.NewDigestWorker(digestService, time.Second*time.Duration(digestInterval))
+	startWorker(ctx, &wg, digestWorker.Run)
 
 	// Handle requests
-	handleRequests(notificationHandler, userHandler, authHandler)
+	handleRequests(notificationHandler, userHandler, authHandler, topicHandler, recurrenceHandler, webhookHandler, adminHandler, channelHandler, preferenceHandler, templateHandler, verificationHandler, passwordResetHandler, hub)
+
+	cancel()
+	wg.Wait()
+	log.Println("Background workers stopped")
+}
+
+// startWorker runs a background worker in its own goroutine and tracks it in wg.
+func startWorker(ctx context.Context, wg *sync.WaitGroup, run func(context.Context)) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		run(ctx)
+	}()
+}
+
+// newChannelRegistry registers the delivery channels listed in channels.enabled.
+// Add in-house channels, such as a pager integration, to the available channels below.
+func newChannelRegistry(cfg *config.Config, webhookRepository *repositories.WebhookRepository, userRepository *repositories.UserRepository) *channels.Registry {
+	enabled := cfg.Channels.Enabled
+	if enabled == "" {
+		enabled = "webhook,email,slack,discord,teams" // Set a default value
+	}
+
+	available := []channels.Channel{
+		channels.NewWebhookChannel(webhookRepository),
+		channels.NewSlackChannel(webhookRepository),
+		channels.NewDiscordChannel(webhookRepository),
+		channels.NewTeamsChannel(webhookRepository),
+		channels.NewLogChannel(),
+	}
+	// Email is only available when an SMTP server is configured
+	if cfg.Mail.Host != "" {
+		mailPort, err := strconv.Atoi(cfg.Mail.Port)
+		if err != nil {
+			mailPort = 587 // Set a default value
+		}
+		mailStartTLS, err := strconv.ParseBool(cfg.Mail.StartTLS)
+		if err != nil {
+			mailStartTLS = true // Set a default value
+		}
+		available = append(available, channels.NewEmailChannel(userRepository, mailer.New(mailer.Config{
+			Host:     cfg.Mail.Host,
+			Port:     mailPort,
+			Username: cfg.Mail.Username,
+			Password: cfg.Mail.Password,
+			From:     cfg.Mail.From,
+			StartTLS: mailStartTLS,
+		})))
+	}
+
+	registry := channels.NewRegistry()
+	for _, channel := range available {
+		for _, name := range strings.Split(enabled, ",") {
+			if strings.TrimSpace(name) != channel.Name() {
+				continue
+			}
+			if err := registry.Register(channel); err != nil {
+				log.Fatalf("Could not register delivery channel: %v", err)
+			}
+			log.Println("Delivery channel enabled:", channel.Name())
+			break
+		}
+	}
+	return registry
 }
@@ -1,11 +1,25 @@
 package middlewares
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
 )
 
+// TokenRevocationChecker reports whether a validly signed token may no longer be used.
+type TokenRevocationChecker interface {
+	IsTokenRevoked(claims jwt.MapClaims) (bool, error)
+}
+
+var revocationChecker TokenRevocationChecker
+
+// SetTokenRevocationChecker makes JWTAuthMiddleware reject the tokens checker reports as revoked.
+func SetTokenRevocationChecker(checker TokenRevocationChecker) {
+	revocationChecker = checker
+}
+
 func JWTAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		err := utils.ValidateJWT(w, r)
@@ -14,6 +28,23 @@ func JWTAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		if revocationChecker != nil {
+			token, err := utils.GetToken(r)
+			if err != nil {
+				utils.RespondWithError(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+			revoked, err := revocationChecker.IsTokenRevoked(token.Claims.(jwt.MapClaims))
+			if err != nil {
+				utils.RespondWithError(w, fmt.Sprintf("Error: failed to check token: %s", err.Error()), http.StatusInternalServerError)
+				return
+			}
+			if revoked {
+				utils.RespondWithError(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+		}
+
 		next.ServeHTTP(w, r)
 	}
 }
@@ -0,0 +1,2 @@
+-- 000006_add_notification_recipients_table.down.sql
+DROP TABLE notification_recipients;
@@ -0,0 +1,11 @@
+-- 000006_add_notification_recipients_table.up.sql
+CREATE TABLE notification_recipients (
+    id SERIAL PRIMARY KEY,
+    notification_id INTEGER NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    recipient_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uc_notification_recipient UNIQUE (notification_id, recipient_id)
+);
+
+CREATE INDEX idx_notification_recipients_recipient_id
+ON notification_recipients (recipient_id);
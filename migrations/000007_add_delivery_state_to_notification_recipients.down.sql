@@ -0,0 +1,9 @@
+-- 000007_add_delivery_state_to_notification_recipients.down.sql
+ALTER TABLE notification_recipients
+DROP COLUMN acknowledged_at;
+
+ALTER TABLE notification_recipients
+DROP COLUMN read_at;
+
+ALTER TABLE notification_recipients
+DROP COLUMN delivered_at;
@@ -0,0 +1,9 @@
+-- 000007_add_delivery_state_to_notification_recipients.up.sql
+ALTER TABLE notification_recipients
+ADD COLUMN delivered_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE notification_recipients
+ADD COLUMN read_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE notification_recipients
+ADD COLUMN acknowledged_at TIMESTAMP WITH TIME ZONE;
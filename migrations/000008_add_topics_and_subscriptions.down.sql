@@ -0,0 +1,7 @@
+-- 000008_add_topics_and_subscriptions.down.sql
+ALTER TABLE notifications
+DROP COLUMN topic;
+
+DROP TABLE subscriptions;
+
+DROP TABLE topics;
@@ -0,0 +1,30 @@
+-- 000008_add_topics_and_subscriptions.up.sql
+CREATE TABLE topics (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uc_topic_name UNIQUE (name)
+);
+
+CREATE TABLE subscriptions (
+    id SERIAL PRIMARY KEY,
+    topic_id INTEGER NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uc_subscription UNIQUE (topic_id, user_id)
+);
+
+CREATE INDEX idx_subscriptions_user_id
+ON subscriptions (user_id);
+
+ALTER TABLE notifications
+ADD COLUMN topic TEXT;
+
+ALTER TABLE notifications
+ADD CONSTRAINT fk_topic
+FOREIGN KEY (topic)
+REFERENCES topics(name)
+ON DELETE SET NULL;
@@ -0,0 +1,8 @@
+-- 000009_add_scheduling_to_notifications.down.sql
+DROP INDEX idx_notifications_pending_send_at;
+
+ALTER TABLE notifications
+DROP COLUMN dispatched_at;
+
+ALTER TABLE notifications
+DROP COLUMN send_at;
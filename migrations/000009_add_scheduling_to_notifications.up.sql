@@ -0,0 +1,14 @@
+-- 000009_add_scheduling_to_notifications.up.sql
+ALTER TABLE notifications
+ADD COLUMN send_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE notifications
+ADD COLUMN dispatched_at TIMESTAMP WITH TIME ZONE;
+
+-- Existing notifications have already been delivered
+UPDATE notifications
+SET dispatched_at = created_at;
+
+CREATE INDEX idx_notifications_pending_send_at
+ON notifications (send_at)
+WHERE dispatched_at IS NULL;
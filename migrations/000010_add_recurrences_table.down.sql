@@ -0,0 +1,2 @@
+-- 000010_add_recurrences_table.down.sql
+DROP TABLE recurrences;
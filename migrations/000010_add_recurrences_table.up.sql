@@ -0,0 +1,23 @@
+-- 000010_add_recurrences_table.up.sql
+CREATE TABLE recurrences (
+    id SERIAL PRIMARY KEY,
+    publisher_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    message TEXT NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 1,
+    topic TEXT,
+    recipients INTEGER[] NOT NULL DEFAULT '{}',
+    cron_expression TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    catch_up_policy TEXT NOT NULL DEFAULT 'once',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_recurrences_next_run_at
+ON recurrences (next_run_at)
+WHERE active;
@@ -0,0 +1,7 @@
+-- 000011_add_expiry_to_notifications.down.sql
+DROP TABLE archived_notifications;
+
+DROP INDEX idx_notifications_expires_at;
+
+ALTER TABLE notifications
+DROP COLUMN expires_at;
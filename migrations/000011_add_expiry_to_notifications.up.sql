@@ -0,0 +1,22 @@
+-- 000011_add_expiry_to_notifications.up.sql
+ALTER TABLE notifications
+ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_notifications_expires_at
+ON notifications (expires_at)
+WHERE expires_at IS NOT NULL;
+
+CREATE TABLE archived_notifications (
+    id INTEGER PRIMARY KEY,
+    title TEXT NOT NULL,
+    message TEXT NOT NULL,
+    priority INTEGER NOT NULL,
+    publisher_id INTEGER,
+    topic TEXT,
+    send_at TIMESTAMP WITH TIME ZONE,
+    dispatched_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    archived_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
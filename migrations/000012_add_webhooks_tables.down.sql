@@ -0,0 +1,4 @@
+-- 000012_add_webhooks_tables.down.sql
+DROP TABLE webhook_deliveries;
+
+DROP TABLE webhooks;
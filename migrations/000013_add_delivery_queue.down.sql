@@ -0,0 +1,10 @@
+-- 000013_add_delivery_queue.down.sql
+ALTER TABLE webhook_deliveries
+DROP COLUMN attempt;
+
+DROP TABLE dead_letter_jobs;
+
+DROP TABLE delivery_jobs;
+
+ALTER TABLE users
+DROP COLUMN is_admin;
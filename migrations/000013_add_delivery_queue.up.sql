@@ -0,0 +1,37 @@
+-- 000013_add_delivery_queue.up.sql
+ALTER TABLE users
+ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE delivery_jobs (
+    id SERIAL PRIMARY KEY,
+    channel TEXT NOT NULL,
+    target_id INTEGER,
+    notification_id INTEGER NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    recipient_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_delivery_jobs_run_at
+ON delivery_jobs (run_at);
+
+CREATE TABLE dead_letter_jobs (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL,
+    channel TEXT NOT NULL,
+    target_id INTEGER,
+    notification_id INTEGER NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    recipient_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE webhook_deliveries
+ADD COLUMN attempt INTEGER NOT NULL DEFAULT 1;
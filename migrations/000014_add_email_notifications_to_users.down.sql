@@ -0,0 +1,3 @@
+-- 000014_add_email_notifications_to_users.down.sql
+ALTER TABLE users
+DROP COLUMN email_notifications;
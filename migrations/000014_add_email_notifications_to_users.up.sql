@@ -0,0 +1,3 @@
+-- 000014_add_email_notifications_to_users.up.sql
+ALTER TABLE users
+ADD COLUMN email_notifications BOOLEAN NOT NULL DEFAULT FALSE;
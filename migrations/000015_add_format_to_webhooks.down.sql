@@ -0,0 +1,3 @@
+-- 000015_add_format_to_webhooks.down.sql
+ALTER TABLE webhooks
+DROP COLUMN format;
@@ -0,0 +1,3 @@
+-- 000015_add_format_to_webhooks.up.sql
+ALTER TABLE webhooks
+ADD COLUMN format TEXT NOT NULL DEFAULT 'json';
@@ -0,0 +1,3 @@
+-- 000016_add_topic_to_webhooks.down.sql
+ALTER TABLE webhooks
+DROP COLUMN topic;
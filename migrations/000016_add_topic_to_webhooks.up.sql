@@ -0,0 +1,3 @@
+-- 000016_add_topic_to_webhooks.up.sql
+ALTER TABLE webhooks
+ADD COLUMN topic TEXT REFERENCES topics(name) ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+-- 000017_add_user_preferences_table.down.sql
+DROP TABLE user_preferences;
@@ -0,0 +1,9 @@
+-- 000018_add_quiet_hours.down.sql
+DROP TABLE held_deliveries;
+
+ALTER TABLE users
+DROP COLUMN timezone,
+DROP COLUMN quiet_hours_start,
+DROP COLUMN quiet_hours_end,
+DROP COLUMN do_not_disturb,
+DROP COLUMN high_priority_breakthrough;
@@ -0,0 +1,19 @@
+-- 000018_add_quiet_hours.up.sql
+ALTER TABLE users
+ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC',
+ADD COLUMN quiet_hours_start TEXT,
+ADD COLUMN quiet_hours_end TEXT,
+ADD COLUMN do_not_disturb BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN high_priority_breakthrough BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE held_deliveries (
+    id SERIAL PRIMARY KEY,
+    notification_id INTEGER NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    recipient_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    release_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (notification_id, recipient_id)
+);
+
+CREATE INDEX idx_held_deliveries_release_at
+ON held_deliveries (release_at);
@@ -0,0 +1,8 @@
+-- 000019_add_digests.down.sql
+DROP TABLE digest_items;
+
+DROP TABLE digests;
+
+ALTER TABLE users
+DROP COLUMN digest_frequency,
+DROP COLUMN digest_next_at;
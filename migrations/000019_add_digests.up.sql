@@ -0,0 +1,23 @@
+-- 000019_add_digests.up.sql
+ALTER TABLE users
+ADD COLUMN digest_frequency TEXT NOT NULL DEFAULT 'off',
+ADD COLUMN digest_next_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_digest_next_at
+ON users (digest_next_at);
+
+CREATE TABLE digests (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    frequency TEXT NOT NULL,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE digest_items (
+    digest_id INTEGER NOT NULL REFERENCES digests(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notification_id INTEGER NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, notification_id)
+);
@@ -0,0 +1,2 @@
+-- 000020_add_templates_table.down.sql
+DROP TABLE templates;
@@ -0,0 +1,11 @@
+-- 000020_add_templates_table.up.sql
+CREATE TABLE templates (
+    id SERIAL PRIMARY KEY,
+    publisher_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    title TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (publisher_id, name)
+);
@@ -0,0 +1,12 @@
+-- 000021_add_translations.down.sql
+ALTER TABLE users
+DROP COLUMN locale;
+
+ALTER TABLE templates
+DROP COLUMN translations;
+
+ALTER TABLE archived_notifications
+DROP COLUMN translations;
+
+ALTER TABLE notifications
+DROP COLUMN translations;
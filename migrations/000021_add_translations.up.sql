@@ -0,0 +1,12 @@
+-- 000021_add_translations.up.sql
+ALTER TABLE notifications
+ADD COLUMN translations JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE archived_notifications
+ADD COLUMN translations JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE templates
+ADD COLUMN translations JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE users
+ADD COLUMN locale TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- 000022_add_idempotency_keys.down.sql
+DROP TABLE idempotency_keys;
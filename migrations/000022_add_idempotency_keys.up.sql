@@ -0,0 +1,15 @@
+-- 000022_add_idempotency_keys.up.sql
+CREATE TABLE idempotency_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    response_status INTEGER,
+    response_headers JSONB,
+    response_body TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, key)
+);
+
+CREATE INDEX idx_idempotency_keys_created_at
+ON idempotency_keys (created_at);
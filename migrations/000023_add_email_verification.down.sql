@@ -0,0 +1,5 @@
+-- 000023_add_email_verification.down.sql
+DROP TABLE email_verification_tokens;
+
+ALTER TABLE users
+DROP COLUMN email_verified_at;
@@ -0,0 +1,2 @@
+-- 000024_add_password_reset_tokens.down.sql
+DROP TABLE password_reset_tokens;
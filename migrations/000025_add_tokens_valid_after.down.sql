@@ -0,0 +1,3 @@
+-- 000025_add_tokens_valid_after.down.sql
+ALTER TABLE users
+DROP COLUMN tokens_valid_after;
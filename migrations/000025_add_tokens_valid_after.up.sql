@@ -0,0 +1,3 @@
+-- 000025_add_tokens_valid_after.up.sql
+ALTER TABLE users
+ADD COLUMN tokens_valid_after TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,6 @@
+-- 000026_add_refresh_tokens.down.sql
+DROP TABLE revoked_tokens;
+
+DROP TABLE refresh_tokens;
+
+DROP TABLE token_families;
@@ -0,0 +1,3 @@
+-- 000027_add_idempotency_key_leases.down.sql
+ALTER TABLE idempotency_keys
+DROP COLUMN locked_until;
@@ -0,0 +1,3 @@
+-- 000027_add_idempotency_key_leases.up.sql
+ALTER TABLE idempotency_keys
+ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE;
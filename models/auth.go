@@ -1,5 +1,7 @@
 package models
 
+import "github.com/akinolaemmanuel49/notify-api/utils"
+
 type AuthCredentials struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -9,3 +11,40 @@ type AuthPasswordChange struct {
 	OldPassword string `json:"old_password"`
 	NewPassword string `json:"new_password"`
 }
+
+type EmailVerification struct {
+	Token string `json:"token"`
+}
+
+type AuthResponse struct {
+	Code    int         `json:"code"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+type PasswordResetConfirmation struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// TokenPair is a short-lived access token and the refresh token that renews it.
+type TokenPair struct {
+	Token        string `json:"token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// JSONWebKeySet is the JWKS other services verify access tokens with. It is served as is, without
+// the usual response envelope, so that standard JWT libraries can consume it.
+type JSONWebKeySet struct {
+	Keys []utils.JSONWebKey `json:"keys"`
+}
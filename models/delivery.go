@@ -0,0 +1,81 @@
+package models
+
+// Names of the built-in delivery channels.
+const (
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+	ChannelSlack   = "slack"
+	ChannelDiscord = "discord"
+	ChannelTeams   = "teams"
+	ChannelLog     = "log"
+)
+
+// ChannelCapabilities describes how a delivery channel is delivered.
+type ChannelCapabilities struct {
+	// Durable channels are delivered through the retry queue, others are sent as soon as a notification is dispatched.
+	Durable bool `json:"durable"`
+	// RichText channels render formatting such as HTML or markdown.
+	RichText bool `json:"rich_text"`
+	// Signed channels sign their payloads so receivers can verify them.
+	Signed bool `json:"signed"`
+}
+
+type ChannelInfo struct {
+	Name         string              `json:"name"`
+	Capabilities ChannelCapabilities `json:"capabilities"`
+}
+
+// DeliveryTarget is a destination of a notification on a channel. TargetID identifies a
+// channel specific destination such as a webhook, and is nil when the recipient is enough.
+type DeliveryTarget struct {
+	RecipientID int64  `json:"recipient_id"`
+	TargetID    *int64 `json:"target_id"`
+}
+
+// Delivery is a single attempt at delivering a notification to a target.
+type Delivery struct {
+	DeliveryTarget
+	NotificationID int64
+	Attempt        int
+}
+
+// DeliveryJob is a queued attempt to deliver a notification to a recipient over a channel.
+type DeliveryJob struct {
+	ID             int64   `json:"id"`
+	Channel        string  `json:"channel"`
+	TargetID       *int64  `json:"target_id"`
+	NotificationID int64   `json:"notification_id"`
+	RecipientID    int64   `json:"recipient_id"`
+	Attempts       int     `json:"attempts"`
+	MaxAttempts    int     `json:"max_attempts"`
+	RunAt          string  `json:"run_at"`
+	LastError      *string `json:"last_error"`
+	CreatedAt      string  `json:"created_at"`
+	UpdatedAt      string  `json:"updated_at"`
+}
+
+// DeadLetterJob is a delivery job that failed on every attempt.
+type DeadLetterJob struct {
+	ID             int64   `json:"id"`
+	JobID          int64   `json:"job_id"`
+	Channel        string  `json:"channel"`
+	TargetID       *int64  `json:"target_id"`
+	NotificationID int64   `json:"notification_id"`
+	RecipientID    int64   `json:"recipient_id"`
+	Attempts       int     `json:"attempts"`
+	LastError      *string `json:"last_error"`
+	CreatedAt      string  `json:"created_at"`
+	FailedAt       string  `json:"failed_at"`
+}
+
+type ChannelResponse struct {
+	Code    int         `json:"code"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
+
+type AdminResponse struct {
+	Code    int         `json:"code"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// digestHour is the local hour digests are sent at. Weekly digests are sent on Mondays.
+const digestHour = 8
+
+func ValidateDigestFrequency(frequency string) error {
+	switch frequency {
+	case DigestOff, DigestDaily, DigestWeekly:
+		return nil
+	}
+	return utils.ErrInvalidDigestFrequency
+}
+
+// NextDigestAt returns when the next digest of the given frequency is due after now, in the user's
+// timezone. It returns nil when digests are off.
+func NextDigestAt(frequency, timezone string, now time.Time) *time.Time {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+
+	var next time.Time
+	switch frequency {
+	case DigestDaily:
+		next = time.Date(local.Year(), local.Month(), local.Day(), digestHour, 0, 0, 0, location)
+		if !next.After(local) {
+			next = time.Date(local.Year(), local.Month(), local.Day()+1, digestHour, 0, 0, 0, location)
+		}
+	case DigestWeekly:
+		days := (int(time.Monday) - int(local.Weekday()) + 7) % 7
+		next = time.Date(local.Year(), local.Month(), local.Day()+days, digestHour, 0, 0, 0, location)
+		if !next.After(local) {
+			next = time.Date(local.Year(), local.Month(), local.Day()+days+7, digestHour, 0, 0, 0, location)
+		}
+	default:
+		return nil
+	}
+	return &next
+}
+
+// DigestPeriod returns how far back a digest of the given frequency collects notifications.
+func DigestPeriod(frequency string) time.Duration {
+	if frequency == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// DigestSubscriber is a user whose digest is due.
+type DigestSubscriber struct {
+	UserID    int64
+	Frequency string
+	Timezone  string
+}
+
+// Digest is a summary of unread Low and Mid priority notifications sent to a user in one email.
+type Digest struct {
+	ID            int64           `json:"id"`
+	UserID        int64           `json:"user_id"`
+	Frequency     string          `json:"frequency"`
+	PeriodStart   string          `json:"period_start"`
+	PeriodEnd     string          `json:"period_end"`
+	Notifications []*Notification `json:"notifications"`
+	CreatedAt     string          `json:"created_at"`
+}
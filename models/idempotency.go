@@ -0,0 +1,43 @@
+package models
+
+import (
+	"net/http"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// IdempotencyKeyHeader is the request header retrying clients send so a request takes effect only once.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// ValidateIdempotencyKey checks that a key is 1-255 printable ASCII characters, such as a UUID.
+func ValidateIdempotencyKey(key string) error {
+	if len(key) == 0 || len(key) > 255 {
+		return utils.ErrInvalidIdempotencyKey
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return utils.ErrInvalidIdempotencyKey
+		}
+	}
+	return nil
+}
+
+// IdempotencyKey records a request made with an idempotency key and, once it has completed, its
+// response. A key without a response belongs to a request that is still in progress, which holds
+// the key until LockedUntil.
+type IdempotencyKey struct {
+	ID              int64
+	UserID          int64
+	Key             string
+	RequestHash     string
+	ResponseStatus  *int
+	ResponseHeaders http.Header
+	ResponseBody    *string
+	LockedUntil     *string
+	CreatedAt       string
+}
+
+// Completed reports whether the response of the request has been stored.
+func (k *IdempotencyKey) Completed() bool {
+	return k.ResponseStatus != nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLocale lowercases a locale tag and uses hyphens as separators, so "pt_BR" becomes "pt-br".
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// ValidateLocale checks that a normalized locale looks like a language tag such as "de" or "pt-br".
+func ValidateLocale(locale string) error {
+	if !localePattern.MatchString(locale) {
+		return fmt.Errorf("%w: %s", utils.ErrInvalidLocale, locale)
+	}
+	return nil
+}
+
+// Translation is the title and message of a notification in one language.
+type Translation struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// Translations maps a locale to a translation. It is stored as a JSON object.
+type Translations map[string]Translation
+
+// Normalize validates the locales of the translations and returns them normalized.
+func (t Translations) Normalize() (Translations, error) {
+	normalized := make(Translations, len(t))
+	for locale, translation := range t {
+		locale = NormalizeLocale(locale)
+		if err := ValidateLocale(locale); err != nil {
+			return nil, err
+		}
+		normalized[locale] = translation
+	}
+	return normalized, nil
+}
+
+// Lookup returns the translation for a locale, falling back from a regional locale such as "pt-br"
+// to its language "pt". It returns false when neither is translated.
+func (t Translations) Lookup(locale string) (Translation, bool) {
+	for locale != "" {
+		if translation, ok := t[locale]; ok {
+			return translation, true
+		}
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return Translation{}, false
+}
+
+func (t Translations) Value() (driver.Value, error) {
+	if t == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(t)
+}
+
+func (t *Translations) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, t)
+	case string:
+		return json.Unmarshal([]byte(value), t)
+	}
+	return errors.New("unsupported type for translations")
+}
+
+// Localize returns a copy of the notification with the title and message in the given locale. The
+// notification's own title and message are the default when it has no matching translation.
+func (n *Notification) Localize(locale string) *Notification {
+	localized := *n
+	localized.Translations = nil
+	if translation, ok := n.Translations.Lookup(locale); ok {
+		localized.Title = translation.Title
+		localized.Message = translation.Message
+	}
+	return &localized
+}
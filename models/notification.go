@@ -2,6 +2,7 @@ package models
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/akinolaemmanuel49/notify-api/utils"
 )
@@ -67,14 +68,78 @@ type Notification struct {
 	Message     string   `json:"message"`
 	Priority    Priority `json:"priority"`
 	PublisherID int64    `json:"publisher_id"`
-	CreatedAt   string   `json:"created_at"`
-	UpdatedAt   string   `json:"updated_at"`
+	Topic       string   `json:"topic,omitempty"`
+	SendAt      *string  `json:"send_at,omitempty"`
+	ExpiresAt   *string  `json:"expires_at,omitempty"`
+	// Translations of the title and message keyed by locale. Recipients see the one matching their locale.
+	Translations Translations `json:"translations,omitempty"`
+	CreatedAt    string       `json:"created_at"`
+	UpdatedAt    string       `json:"updated_at"`
+}
+
+// InboxNotification is a notification as seen by one of its recipients.
+type InboxNotification struct {
+	Notification
+	DeliveredAt    *string `json:"delivered_at"`
+	ReadAt         *string `json:"read_at"`
+	AcknowledgedAt *string `json:"acknowledged_at"`
 }
 
 type NotificationInput struct {
-	Title    string   `json:"title"`
-	Message  string   `json:"message"`
-	Priority Priority `json:"priority"`
+	Title      string     `json:"title"`
+	Message    string     `json:"message"`
+	Priority   Priority   `json:"priority"`
+	Recipients []int64    `json:"recipients"`
+	Topic      string     `json:"topic"`
+	SendAt     *time.Time `json:"send_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	// TTL is the number of seconds the notification stays visible after it is released.
+	TTL          int64        `json:"ttl"`
+	Translations Translations `json:"translations,omitempty"`
+	// Template names one of the publisher's templates to render the title and message from, with Variables.
+	Template  string                 `json:"template,omitempty"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// IsScheduled reports whether the notification should be held back until its send_at time.
+func (notificationInput *NotificationInput) IsScheduled(now time.Time) bool {
+	return notificationInput.SendAt != nil && notificationInput.SendAt.After(now)
+}
+
+// Expiry returns the time the notification expires at, or nil if it never expires.
+// An explicit expires_at takes precedence over a TTL.
+func (notificationInput *NotificationInput) Expiry(now time.Time) *time.Time {
+	if notificationInput.ExpiresAt != nil {
+		return notificationInput.ExpiresAt
+	}
+	if notificationInput.TTL <= 0 {
+		return nil
+	}
+	releasedAt := now
+	if notificationInput.IsScheduled(now) {
+		releasedAt = *notificationInput.SendAt
+	}
+	expiresAt := releasedAt.Add(time.Duration(notificationInput.TTL) * time.Second)
+	return &expiresAt
+}
+
+// ValidateExpiry checks that the notification does not expire before it is released.
+func (notificationInput *NotificationInput) ValidateExpiry(now time.Time) error {
+	if notificationInput.TTL < 0 {
+		return utils.ErrInvalidExpiry
+	}
+	expiresAt := notificationInput.Expiry(now)
+	if expiresAt == nil {
+		return nil
+	}
+	releasedAt := now
+	if notificationInput.IsScheduled(now) {
+		releasedAt = *notificationInput.SendAt
+	}
+	if !expiresAt.After(releasedAt) {
+		return utils.ErrInvalidExpiry
+	}
+	return nil
 }
 
 type NotificationResponse struct {
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// Preferences decide which delivery channels a user receives notifications on. The inbox and live
+// streams always receive every notification, so an empty channel list means "only in the inbox".
+type Preferences struct {
+	// Priorities maps a priority label (LOW, MID or HIGH) to channels.
+	Priorities map[string][]string `json:"priorities"`
+	// Topics maps a topic name to channels. A topic rule takes precedence over the priority rule.
+	Topics    map[string][]string `json:"topics"`
+	UpdatedAt *string             `json:"updated_at,omitempty"`
+}
+
+// ChannelsFor returns the channels a notification with the given priority and topic should be
+// delivered on. It returns false when no rule applies.
+func (p *Preferences) ChannelsFor(priority Priority, topic string) ([]string, bool) {
+	if topic != "" {
+		if channels, ok := p.Topics[topic]; ok {
+			return channels, true
+		}
+	}
+	label, err := priority.String()
+	if err != nil {
+		return nil, false
+	}
+	channels, ok := p.Priorities[label]
+	return channels, ok
+}
+
+// Validate checks the priority labels and topic names of the rules, and that every channel is known.
+func (p *Preferences) Validate(isChannel func(name string) bool) error {
+	for label, channels := range p.Priorities {
+		switch label {
+		case "LOW", "MID", "HIGH":
+		default:
+			return utils.ErrInvalidPriorityLabel
+		}
+		if err := validateChannels(channels, isChannel); err != nil {
+			return err
+		}
+	}
+	for topic, channels := range p.Topics {
+		if err := ValidateTopicName(topic); err != nil {
+			return err
+		}
+		if err := validateChannels(channels, isChannel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateChannels(channels []string, isChannel func(name string) bool) error {
+	for _, channel := range channels {
+		if !isChannel(channel) {
+			return utils.ErrUnknownChannel
+		}
+	}
+	return nil
+}
+
+// RecipientPreferences are the preferences used to route a notification to a recipient.
+type RecipientPreferences struct {
+	Preferences
+	EmailNotifications bool
+	DigestFrequency    string
+	QuietHours         QuietHours
+}
+
+type PreferenceResponse struct {
+	Code    int         `json:"code"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// QuietHours decide when a user does not want to be disturbed. Outbound deliveries are held during
+// the daily window and while do-not-disturb is on, and released once they end.
+type QuietHours struct {
+	Timezone string
+	// Start and End are local times formatted as HH:MM. The window may wrap around midnight.
+	Start                    *string
+	End                      *string
+	DoNotDisturb             bool
+	HighPriorityBreakthrough bool
+}
+
+// HoldUntil reports whether a notification with the given priority must be held at now, and until
+// when. A held notification without a release time waits until do-not-disturb is turned off.
+func (q *QuietHours) HoldUntil(priority Priority, now time.Time) (bool, *time.Time) {
+	if priority == High && q.HighPriorityBreakthrough {
+		return false, nil
+	}
+	if q.DoNotDisturb {
+		return true, nil
+	}
+	if q.Start == nil || q.End == nil {
+		return false, nil
+	}
+	start, err := ParseClockTime(*q.Start)
+	if err != nil {
+		return false, nil
+	}
+	end, err := ParseClockTime(*q.End)
+	if err != nil || start == end {
+		return false, nil
+	}
+	location, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	var quiet bool
+	if start < end {
+		quiet = minute >= start && minute < end
+	} else {
+		quiet = minute >= start || minute < end
+	}
+	if !quiet {
+		return false, nil
+	}
+
+	releaseAt := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, location)
+	if !releaseAt.After(local) {
+		releaseAt = time.Date(local.Year(), local.Month(), local.Day()+1, end/60, end%60, 0, 0, location)
+	}
+	return true, &releaseAt
+}
+
+// ParseClockTime parses a local time formatted as HH:MM and returns the minutes since midnight.
+func ParseClockTime(value string) (int, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, utils.ErrInvalidQuietHours
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+func ValidateTimezone(timezone string) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("%w: %s", utils.ErrInvalidTimezone, timezone)
+	}
+	return nil
+}
+
+// HeldDelivery is a notification held back from a recipient's outbound channels.
+type HeldDelivery struct {
+	ID             int64   `json:"id"`
+	NotificationID int64   `json:"notification_id"`
+	RecipientID    int64   `json:"recipient_id"`
+	ReleaseAt      *string `json:"release_at"`
+	CreatedAt      string  `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Frame types exchanged over the notifications WebSocket.
+const (
+	FrameSubscribe    = "subscribe"
+	FrameUnsubscribe  = "unsubscribe"
+	FrameAck          = "ack"
+	FrameRead         = "read"
+	FrameNotification = "notification"
+	FrameSubscribed   = "subscribed"
+	FrameUnsubscribed = "unsubscribed"
+	FrameAcked        = "acked"
+	FrameError        = "error"
+)
+
+type RealtimeFrame struct {
+	Type           string        `json:"type"`
+	PublisherID    int64         `json:"publisher_id,omitempty"`
+	Topic          string        `json:"topic,omitempty"`
+	NotificationID int64         `json:"notification_id,omitempty"`
+	Data           *Notification `json:"data,omitempty"`
+	Error          string        `json:"error,omitempty"`
+}
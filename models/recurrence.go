@@ -0,0 +1,59 @@
+package models
+
+import (
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// Catch-up policies decide what happens to firings that were missed while the server was down.
+const (
+	// CatchUpSkip drops missed firings and waits for the next one.
+	CatchUpSkip = "skip"
+	// CatchUpOnce fires a single notification for all missed firings.
+	CatchUpOnce = "once"
+	// CatchUpAll fires one notification for every missed firing.
+	CatchUpAll = "all"
+)
+
+func ValidateCatchUpPolicy(policy string) error {
+	switch policy {
+	case CatchUpSkip, CatchUpOnce, CatchUpAll:
+		return nil
+	}
+	return utils.ErrInvalidCatchUpPolicy
+}
+
+type Recurrence struct {
+	ID             int64    `json:"id"`
+	PublisherID    int64    `json:"publisher_id"`
+	Title          string   `json:"title"`
+	Message        string   `json:"message"`
+	Priority       Priority `json:"priority"`
+	Topic          string   `json:"topic,omitempty"`
+	Recipients     []int64  `json:"recipients"`
+	CronExpression string   `json:"cron_expression"`
+	Timezone       string   `json:"timezone"`
+	CatchUpPolicy  string   `json:"catch_up_policy"`
+	Active         bool     `json:"active"`
+	NextRunAt      string   `json:"next_run_at"`
+	LastRunAt      *string  `json:"last_run_at"`
+	CreatedAt      string   `json:"created_at"`
+	UpdatedAt      string   `json:"updated_at"`
+}
+
+type RecurrenceInput struct {
+	Title          string   `json:"title"`
+	Message        string   `json:"message"`
+	Priority       Priority `json:"priority"`
+	Topic          string   `json:"topic"`
+	Recipients     []int64  `json:"recipients"`
+	CronExpression string   `json:"cron_expression"`
+	Timezone       string   `json:"timezone"`
+	CatchUpPolicy  string   `json:"catch_up_policy"`
+	Active         *bool    `json:"active"`
+}
+
+type RecurrenceResponse struct {
+	Code    int         `json:"code"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "regexp"
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// IsSlug reports whether name is a lowercase slug of at most 64 letters, digits, dots, underscores
+// and hyphens that starts with a letter or digit, as topic and template names are.
+func IsSlug(name string) bool {
+	return slugPattern.MatchString(name)
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// ValidateTemplateName checks that a template name is a lowercase slug such as "deploy-finished".
+func ValidateTemplateName(name string) error {
+	if !IsSlug(name) {
+		return utils.ErrInvalidTemplateName
+	}
+	return nil
+}
+
+// Template is a named title and message a publisher renders notifications from. Both are
+// text/template sources, e.g. "Deploy of {{.service}} finished", and so are their translations.
+type Template struct {
+	ID           int64        `json:"id"`
+	PublisherID  int64        `json:"publisher_id"`
+	Name         string       `json:"name"`
+	Title        string       `json:"title"`
+	Message      string       `json:"message"`
+	Translations Translations `json:"translations,omitempty"`
+	CreatedAt    string       `json:"created_at"`
+	UpdatedAt    string       `json:"updated_at"`
+}
+
+type TemplateInput struct {
+	Name         string       `json:"name"`
+	Title        string       `json:"title"`
+	Message      string       `json:"message"`
+	Translations Translations `json:"translations"`
+}
+
+type TemplateResponse struct {
+	Code    int         `json:"code"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// ValidateTopicName checks that a topic name is a lowercase slug such as "deploys" or "billing.invoices".
+func ValidateTopicName(name string) error {
+	if !IsSlug(name) {
+		return utils.ErrInvalidTopicName
+	}
+	return nil
+}
+
+type Topic struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CreatedBy   int64  `json:"created_by"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type TopicInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type Subscription struct {
+	ID        int64  `json:"id"`
+	Topic     string `json:"topic"`
+	UserID    int64  `json:"user_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+type TopicResponse struct {
+	Code    int         `json:"code"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
@@ -6,23 +6,34 @@ type User struct {
 	LastName     string `json:"last_name"`
 	Email        string `json:"email"`
 	PasswordHash string `json:"password"`
+	Timezone     string `json:"timezone"`
 	CreatedAt    string `json:"created_at"`
 	UpdatedAt    string `json:"updated_at"`
 }
 
 type UserProfile struct {
-	ID        int64  `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID                       int64   `json:"id"`
+	FirstName                string  `json:"first_name"`
+	LastName                 string  `json:"last_name"`
+	Email                    string  `json:"email"`
+	EmailNotifications       bool    `json:"email_notifications"`
+	DigestFrequency          string  `json:"digest_frequency"`
+	Timezone                 string  `json:"timezone"`
+	Locale                   string  `json:"locale"`
+	QuietHoursStart          *string `json:"quiet_hours_start"`
+	QuietHoursEnd            *string `json:"quiet_hours_end"`
+	DoNotDisturb             bool    `json:"do_not_disturb"`
+	HighPriorityBreakthrough bool    `json:"high_priority_breakthrough"`
+	EmailVerifiedAt          *string `json:"email_verified_at"`
+	CreatedAt                string  `json:"created_at"`
+	UpdatedAt                string  `json:"updated_at"`
 }
 
 type UserInput struct {
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	Email     string `json:"email"`
+	Timezone  string `json:"timezone"`
 }
 
 type UserInputWithPassword struct {
@@ -30,6 +41,7 @@ type UserInputWithPassword struct {
 	LastName  string `json:"last_name"`
 	Email     string `json:"email"`
 	Password  string `json:"password"`
+	Timezone  string `json:"timezone"`
 }
 
 type UserResponse struct {
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// WebhookEventNotificationCreated is sent when a notification is delivered to a recipient.
+const WebhookEventNotificationCreated = "notification.created"
+
+// Webhook formats decide which channel delivers to a webhook and the shape of its payload.
+const (
+	// WebhookFormatJSON webhooks receive a signed WebhookPayload.
+	WebhookFormatJSON = "json"
+	// WebhookFormatSlack webhooks are Slack-compatible incoming webhooks.
+	WebhookFormatSlack = "slack"
+	// WebhookFormatDiscord webhooks are Discord webhooks.
+	WebhookFormatDiscord = "discord"
+	// WebhookFormatTeams webhooks are Microsoft Teams incoming webhook connectors.
+	WebhookFormatTeams = "teams"
+)
+
+func ValidateWebhookFormat(format string) error {
+	switch format {
+	case WebhookFormatJSON, WebhookFormatSlack, WebhookFormatDiscord, WebhookFormatTeams:
+		return nil
+	}
+	return utils.ErrInvalidWebhookFormat
+}
+
+// ValidateWebhookURL checks that a webhook URL is an absolute http or https URL that does not name
+// a local or non-public host. Host names are checked again against the addresses they resolve to
+// when a webhook is called.
+func ValidateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return utils.ErrInvalidWebhookURL
+	}
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return utils.ErrWebhookURLNotPublic
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !utils.IsPublicIP(addr) {
+		return utils.ErrWebhookURLNotPublic
+	}
+	return nil
+}
+
+type Webhook struct {
+	ID        int64  `json:"id"`
+	UserID    int64  `json:"user_id"`
+	URL       string `json:"url"`
+	Format    string `json:"format"`
+	Topic     string `json:"topic,omitempty"`
+	Secret    string `json:"secret,omitempty"`
+	Active    bool   `json:"active"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type WebhookInput struct {
+	URL    string `json:"url"`
+	Format string `json:"format"`
+	Topic  string `json:"topic"`
+	Secret string `json:"secret"`
+	Active *bool  `json:"active"`
+}
+
+// WebhookPayload is the JSON body POSTed to a webhook endpoint.
+type WebhookPayload struct {
+	Event       string        `json:"event"`
+	WebhookID   int64         `json:"webhook_id"`
+	RecipientID int64         `json:"recipient_id"`
+	Timestamp   int64         `json:"timestamp"`
+	Data        *Notification `json:"data"`
+}
+
+// WebhookDelivery records a single attempt to deliver a notification to a webhook.
+type WebhookDelivery struct {
+	ID             int64   `json:"id"`
+	WebhookID      int64   `json:"webhook_id"`
+	NotificationID *int64  `json:"notification_id"`
+	Attempt        int     `json:"attempt"`
+	StatusCode     *int    `json:"status_code"`
+	Success        bool    `json:"success"`
+	Error          *string `json:"error"`
+	DurationMS     int64   `json:"duration_ms"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+type WebhookResponse struct {
+	Code    int         `json:"code"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want error
+	}{
+		{"https://hooks.slack.com/services/T000/B000/XXXX", nil},
+		{"http://example.com:8080/hook", nil},
+		{"ftp://example.com/hook", utils.ErrInvalidWebhookURL},
+		{"/relative", utils.ErrInvalidWebhookURL},
+		{"http://localhost:8080/hook", utils.ErrWebhookURLNotPublic},
+		{"http://api.LOCALHOST./hook", utils.ErrWebhookURLNotPublic},
+		{"http://127.0.0.1/hook", utils.ErrWebhookURLNotPublic},
+		{"http://169.254.169.254/latest/meta-data", utils.ErrWebhookURLNotPublic},
+		{"http://[::1]:8080/hook", utils.ErrWebhookURLNotPublic},
+		{"http://10.0.0.5/hook", utils.ErrWebhookURLNotPublic},
+	}
+	for _, tt := range tests {
+		if err := ValidateWebhookURL(tt.url); !errors.Is(err, tt.want) {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want %v", tt.url, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Package passwords decides which passwords users may choose.
+package passwords
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// maxBytes is the longest password bcrypt can hash; longer ones would be silently truncated.
+const maxBytes = 72
+
+// Policy rejects passwords that are too short or appear in a list of breached passwords.
+type Policy struct {
+	minLength int
+	breached  map[[sha1.Size]byte]struct{}
+}
+
+// NewPolicy creates a policy requiring at least minLength characters. When breachedListPath is not
+// empty, passwords listed in that file are rejected as well. The file holds one password per line,
+// either in the clear or as a SHA-1 hex digest, optionally followed by ":<count>" as in the
+// Have I Been Pwned downloads. The list is kept in memory, so use a list of the most common passwords
+// rather than a full dump.
+func NewPolicy(minLength int, breachedListPath string) (*Policy, error) {
+	policy := &Policy{
+		minLength: minLength,
+		breached:  map[[sha1.Size]byte]struct{}{},
+	}
+	if breachedListPath == "" {
+		return policy, nil
+	}
+
+	file, err := os.Open(breachedListPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		policy.breached[digest(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading breached password list: %w", err)
+	}
+	return policy, nil
+}
+
+// Validate checks that a password may be used.
+func (p *Policy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.minLength {
+		return fmt.Errorf("%w: it must be at least %d characters long", utils.ErrPasswordTooShort, p.minLength)
+	}
+	if len(password) > maxBytes {
+		return fmt.Errorf("%w: it must be at most %d bytes long", utils.ErrPasswordTooLong, maxBytes)
+	}
+	if _, ok := p.breached[sha1.Sum([]byte(password))]; ok {
+		return utils.ErrPasswordBreached
+	}
+	return nil
+}
+
+// digest returns the SHA-1 digest of a line of the breached password list.
+func digest(line string) [sha1.Size]byte {
+	hash, _, _ := strings.Cut(line, ":")
+	var sum [sha1.Size]byte
+	if len(hash) == hex.EncodedLen(sha1.Size) {
+		if _, err := hex.Decode(sum[:], []byte(hash)); err == nil {
+			return sum
+		}
+	}
+	return sha1.Sum([]byte(line))
+}
@@ -0,0 +1,181 @@
+package realtime
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+)
+
+// clientBufferSize is the number of notifications a client may fall behind by
+// before it is disconnected.
+const clientBufferSize = 32
+
+// Client is a live connection of a single user.
+type Client struct {
+	UserID int64
+	// Locale is the locale notifications are shown to the user in.
+	Locale string
+	// Send receives every notification addressed to the user or published to one
+	// of the client's subscriptions. It is closed when the client is closed,
+	// falls too far behind or the hub is closed.
+	Send chan *models.Notification
+
+	hub           *Hub
+	subscriptions map[string]struct{}
+	release       sync.Once
+}
+
+// Close stops deliveries to the client and closes its Send channel.
+func (c *Client) Close() {
+	c.hub.Unregister(c)
+}
+
+// Hub keeps track of live clients and fans new notifications out to them.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[*Client]struct{}
+	closed  bool
+	// active counts clients that have not been released yet, so shutdown can
+	// wait for connections to drain.
+	active sync.WaitGroup
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		clients: make(map[*Client]struct{}),
+	}
+}
+
+// PublisherSubscription is the subscription key for notifications from a publisher.
+func PublisherSubscription(publisherID int64) string {
+	return "publisher:" + strconv.FormatInt(publisherID, 10)
+}
+
+// TopicSubscription is the subscription key for notifications posted to a topic.
+func TopicSubscription(topic string) string {
+	return "topic:" + topic
+}
+
+// Register adds a new client for the given user. It returns nil once the hub has been closed.
+// Every registered client must eventually be released.
+func (h *Hub) Register(userID int64) *Client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil
+	}
+	client := &Client{
+		UserID:        userID,
+		Send:          make(chan *models.Notification, clientBufferSize),
+		hub:           h,
+		subscriptions: make(map[string]struct{}),
+	}
+	h.clients[client] = struct{}{}
+	h.active.Add(1)
+	return client
+}
+
+// Unregister removes a client from the hub. It is safe to call more than once.
+func (h *Hub) Unregister(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.removeLocked(client)
+}
+
+// Release unregisters a client and marks its connection as fully torn down.
+// It is safe to call more than once.
+func (h *Hub) Release(client *Client) {
+	h.Unregister(client)
+	client.release.Do(h.active.Done)
+}
+
+// Subscribe adds a subscription to a client so it also receives notifications matching key.
+func (h *Hub) Subscribe(client *Client, key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client.subscriptions[key] = struct{}{}
+}
+
+// Unsubscribe removes a subscription from a client.
+func (h *Hub) Unsubscribe(client *Client, key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(client.subscriptions, key)
+}
+
+// Publish delivers a notification to every live client of its recipients. Notifications
+// posted to a topic or addressed to no one are also delivered to every client subscribed
+// to their publisher or topic; ones addressed to specific recipients only reach them.
+// Clients that cannot keep up are disconnected so they can reconnect and replay.
+func (h *Hub) Publish(notification *models.Notification, recipientIDs []int64) {
+	recipients := make(map[int64]struct{}, len(recipientIDs))
+	for _, recipientID := range recipientIDs {
+		recipients[recipientID] = struct{}{}
+	}
+	// Anyone may subscribe to a topic, so topic notifications are never private
+	broadcast := notification.Topic != "" || len(recipientIDs) == 0
+	publisherKey := PublisherSubscription(notification.PublisherID)
+	topicKey := ""
+	if notification.Topic != "" {
+		topicKey = TopicSubscription(notification.Topic)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients {
+		_, isRecipient := recipients[client.UserID]
+		_, isPublisherSubscriber := client.subscriptions[publisherKey]
+		_, isTopicSubscriber := client.subscriptions[topicKey]
+		if !isRecipient && !(broadcast && (isPublisherSubscriber || isTopicSubscriber)) {
+			continue
+		}
+		select {
+		case client.Send <- notification:
+		default:
+			h.removeLocked(client)
+		}
+	}
+}
+
+// Close disconnects every client and rejects new registrations.
+// Notifications already buffered for a client remain readable from its Send channel.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for client := range h.clients {
+		h.removeLocked(client)
+	}
+}
+
+// Wait blocks until every registered client has been released or the context is done.
+func (h *Hub) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.active.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Hub) removeLocked(client *Client) {
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	delete(h.clients, client)
+	close(client.Send)
+}
@@ -0,0 +1,49 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+)
+
+func received(client *Client) int {
+	count := 0
+	for {
+		select {
+		case <-client.Send:
+			count++
+		default:
+			return count
+		}
+	}
+}
+
+func TestPublishKeepsRecipientNotificationsPrivate(t *testing.T) {
+	hub := NewHub()
+	recipient := hub.Register(2)
+	follower := hub.Register(3)
+	hub.Subscribe(follower, PublisherSubscription(1))
+	hub.Subscribe(follower, TopicSubscription("deploys"))
+
+	tests := []struct {
+		name          string
+		notification  *models.Notification
+		recipientIDs  []int64
+		wantRecipient int
+		wantFollower  int
+	}{
+		{"addressed to a recipient", &models.Notification{PublisherID: 1}, []int64{2}, 1, 0},
+		{"addressed to no one", &models.Notification{PublisherID: 1}, nil, 0, 1},
+		{"posted to a topic", &models.Notification{PublisherID: 1, Topic: "deploys"}, []int64{2}, 1, 1},
+		{"from another publisher", &models.Notification{PublisherID: 4}, nil, 0, 0},
+	}
+	for _, tt := range tests {
+		hub.Publish(tt.notification, tt.recipientIDs)
+		if got := received(recipient); got != tt.wantRecipient {
+			t.Errorf("%s: recipient received %d notifications, want %d", tt.name, got, tt.wantRecipient)
+		}
+		if got := received(follower); got != tt.wantFollower {
+			t.Errorf("%s: follower received %d notifications, want %d", tt.name, got, tt.wantFollower)
+		}
+	}
+}
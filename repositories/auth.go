@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/akinolaemmanuel49/notify-api/models"
 	"github.com/akinolaemmanuel49/notify-api/utils"
@@ -43,3 +44,212 @@ func (r *AuthRepository) AuthenticateUser(credentials *models.AuthCredentials) (
 	}
 	return false, 0, utils.ErrInvalidCredentials
 }
+
+// CreateTokenFamily starts a session for a user with its first refresh token. Every refresh token
+// the session is renewed with belongs to the same family.
+func (r *AuthRepository) CreateTokenFamily(userID int64, familyID, tokenHash string, expiresAt time.Time) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO token_families(
+		id,
+		user_id,
+		created_at
+	) VALUES (($1), ($2), ($3))`
+
+	_, err = tx.Exec(query, familyID, userID, currentTime)
+	if err != nil {
+		log.Println("Error inserting token family:", err)
+		return err
+	}
+
+	query = `
+	INSERT INTO refresh_tokens(
+		family_id,
+		token_hash,
+		expires_at,
+		created_at
+	) VALUES (($1), ($2), ($3), ($4))`
+
+	_, err = tx.Exec(query, familyID, tokenHash, expiresAt.UTC().Format(time.RFC3339), currentTime)
+	if err != nil {
+		log.Println("Error inserting refresh token:", err)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Println("Error committing token family:", err)
+	}
+	return err
+}
+
+// RotateRefreshToken uses up a refresh token and stores the one replacing it in the same family.
+// It returns the user and family of the token. A token that was used before has been stolen or
+// replayed, so its whole family is revoked and ErrRefreshTokenReused is returned.
+func (r *AuthRepository) RotateRefreshToken(tokenHash, newTokenHash string, expiresAt, now time.Time) (int64, string, error) {
+	currentTime := now.UTC().Format(time.RFC3339)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return 0, "", err
+	}
+	defer tx.Rollback()
+
+	// Locking the token makes concurrent refreshes with it wait, so only one of them can use it
+	query := `
+	SELECT f.user_id, f.id, f.revoked_at IS NOT NULL, rt.used_at IS NOT NULL, rt.expires_at <= ($2)
+	FROM refresh_tokens rt
+	INNER JOIN token_families f ON f.id = rt.family_id
+	WHERE rt.token_hash = ($1)
+	FOR UPDATE OF rt`
+
+	var userID int64
+	var familyID string
+	var revoked, used, expired bool
+	err = tx.QueryRow(query, tokenHash, currentTime).Scan(&userID, &familyID, &revoked, &used, &expired)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, "", utils.ErrInvalidRefreshToken
+		}
+		log.Println("Error retrieving refresh token:", err)
+		return 0, "", err
+	}
+	if revoked || expired {
+		return 0, "", utils.ErrInvalidRefreshToken
+	}
+	if used {
+		query = `UPDATE token_families SET revoked_at = ($1) WHERE id = ($2)`
+
+		_, err = tx.Exec(query, currentTime, familyID)
+		if err != nil {
+			log.Println("Error revoking token family:", err)
+			return 0, "", err
+		}
+		err = tx.Commit()
+		if err != nil {
+			log.Println("Error committing token family revocation:", err)
+			return 0, "", err
+		}
+		return 0, "", utils.ErrRefreshTokenReused
+	}
+
+	query = `UPDATE refresh_tokens SET used_at = ($1) WHERE token_hash = ($2)`
+
+	_, err = tx.Exec(query, currentTime, tokenHash)
+	if err != nil {
+		log.Println("Error using refresh token:", err)
+		return 0, "", err
+	}
+
+	query = `
+	INSERT INTO refresh_tokens(
+		family_id,
+		token_hash,
+		expires_at,
+		created_at
+	) VALUES (($1), ($2), ($3), ($4))`
+
+	_, err = tx.Exec(query, familyID, newTokenHash, expiresAt.UTC().Format(time.RFC3339), currentTime)
+	if err != nil {
+		log.Println("Error inserting refresh token:", err)
+		return 0, "", err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Println("Error committing refresh token rotation:", err)
+		return 0, "", err
+	}
+	return userID, familyID, nil
+}
+
+// RevokeTokenFamily ends a session, so that neither its refresh tokens nor the access tokens
+// issued with them can be used any more.
+func (r *AuthRepository) RevokeTokenFamily(familyID string, now time.Time) error {
+	query := `UPDATE token_families SET revoked_at = ($1) WHERE id = ($2) AND revoked_at IS NULL`
+
+	_, err := r.db.Exec(query, now.UTC().Format(time.RFC3339), familyID)
+	if err != nil {
+		log.Println("Error revoking token family:", err)
+	}
+	return err
+}
+
+// RevokeToken revokes a single access token until it expires.
+func (r *AuthRepository) RevokeToken(tokenID string, expiresAt time.Time) error {
+	query := `
+	INSERT INTO revoked_tokens(jti, expires_at)
+	VALUES (($1), ($2))
+	ON CONFLICT (jti) DO NOTHING`
+
+	_, err := r.db.Exec(query, tokenID, expiresAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		log.Println("Error revoking token:", err)
+	}
+	return err
+}
+
+// GetTokenStatus retrieves what decides whether an access token can still be used: the time before
+// which tokens issued to its user are revoked, which is nil when none are, and whether the token
+// itself or its session has been revoked.
+func (r *AuthRepository) GetTokenStatus(userID int64, tokenID, familyID string) (*time.Time, bool, error) {
+	query := `
+	SELECT u.tokens_valid_after,
+		EXISTS (SELECT 1 FROM revoked_tokens rt WHERE rt.jti = ($2))
+		OR EXISTS (SELECT 1 FROM token_families f WHERE f.id = ($3) AND f.revoked_at IS NOT NULL)
+	FROM users u WHERE u.id = ($1)`
+
+	var validAfter *time.Time
+	var revoked bool
+	err := r.db.QueryRow(query, userID, tokenID, familyID).Scan(&validAfter, &revoked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, utils.ErrNotFound
+		}
+		log.Println("Error retrieving token status:", err)
+		return nil, false, err
+	}
+	return validAfter, revoked, nil
+}
+
+// DeleteExpiredTokens removes revoked access tokens that have expired anyway and sessions whose
+// refresh tokens have all expired. It returns how many were removed.
+func (r *AuthRepository) DeleteExpiredTokens(now time.Time) (int64, error) {
+	currentTime := now.UTC().Format(time.RFC3339)
+
+	query := `DELETE FROM revoked_tokens WHERE expires_at <= ($1)`
+
+	result, err := r.db.Exec(query, currentTime)
+	if err != nil {
+		log.Println("Error deleting expired revoked tokens:", err)
+		return 0, err
+	}
+	deletedTokens, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	query = `
+	DELETE FROM token_families f
+	WHERE NOT EXISTS (SELECT 1 FROM refresh_tokens rt WHERE rt.family_id = f.id AND rt.expires_at > ($1))`
+
+	result, err = r.db.Exec(query, currentTime)
+	if err != nil {
+		log.Println("Error deleting expired token families:", err)
+		return deletedTokens, err
+	}
+	deletedFamilies, err := result.RowsAffected()
+	if err != nil {
+		return deletedTokens, err
+	}
+	return deletedTokens + deletedFamilies, nil
+}
@@ -0,0 +1,373 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/lib/pq"
+)
+
+// deliveryJobColumns lists the columns read by scanDeliveryJob.
+const deliveryJobColumns = `id, channel, target_id, notification_id, recipient_id, attempts, max_attempts, run_at,
+	last_error, created_at, updated_at`
+
+// deadLetterJobColumns lists the columns read by scanDeadLetterJob.
+const deadLetterJobColumns = `id, job_id, channel, target_id, notification_id, recipient_id, attempts, last_error,
+	created_at, failed_at`
+
+type DeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewDeliveryRepository(db *sql.DB) *DeliveryRepository {
+	return &DeliveryRepository{
+		db: db,
+	}
+}
+
+// EnqueueJobs queues a job on channel for every target of a notification.
+func (r *DeliveryRepository) EnqueueJobs(channel string, notificationID int64, targets []models.DeliveryTarget, maxAttempts int) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	recipientIDs := make([]int64, len(targets))
+	// Targets without a channel specific destination are sent as 0
+	targetIDs := make([]int64, len(targets))
+	for i, target := range targets {
+		recipientIDs[i] = target.RecipientID
+		if target.TargetID != nil {
+			targetIDs[i] = *target.TargetID
+		}
+	}
+
+	query := `
+	INSERT INTO delivery_jobs(
+		channel,
+		target_id,
+		notification_id,
+		recipient_id,
+		max_attempts,
+		run_at,
+		created_at,
+		updated_at
+	)
+	SELECT ($1)::TEXT, NULLIF(t.target_id, 0), ($2)::INTEGER, t.recipient_id, ($3)::INTEGER, ($4)::TIMESTAMP WITH TIME ZONE, ($4)::TIMESTAMP WITH TIME ZONE, ($4)::TIMESTAMP WITH TIME ZONE
+	FROM unnest($5::INTEGER[], $6::INTEGER[]) AS t(recipient_id, target_id)`
+
+	_, err := r.db.Exec(query, channel, notificationID, maxAttempts, currentTime, pq.Array(recipientIDs), pq.Array(targetIDs))
+	if err != nil {
+		log.Println("Error enqueueing delivery jobs:", err)
+	}
+	return err
+}
+
+// HoldDeliveries holds a notification back from recipients until the release time given for each of
+// them. Recipients without a release time are held until they turn do-not-disturb off.
+func (r *DeliveryRepository) HoldDeliveries(notificationID int64, releaseAts map[int64]*time.Time) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	recipientIDs := make([]int64, 0, len(releaseAts))
+	// Recipients without a release time are sent as an empty string
+	releases := make([]string, 0, len(releaseAts))
+	for recipientID, releaseAt := range releaseAts {
+		recipientIDs = append(recipientIDs, recipientID)
+		if releaseAt != nil {
+			releases = append(releases, releaseAt.UTC().Format(time.RFC3339))
+		} else {
+			releases = append(releases, "")
+		}
+	}
+
+	query := `
+	INSERT INTO held_deliveries(
+		notification_id,
+		recipient_id,
+		release_at,
+		created_at
+	)
+	SELECT ($1)::INTEGER, h.recipient_id, NULLIF(h.release_at, '')::TIMESTAMP WITH TIME ZONE, ($2)::TIMESTAMP WITH TIME ZONE
+	FROM unnest($3::INTEGER[], $4::TEXT[]) AS h(recipient_id, release_at)
+	ON CONFLICT (notification_id, recipient_id) DO NOTHING`
+
+	_, err := r.db.Exec(query, notificationID, currentTime, pq.Array(recipientIDs), pq.Array(releases))
+	if err != nil {
+		log.Println("Error holding deliveries:", err)
+	}
+	return err
+}
+
+// ReleaseHeldDeliveries removes and returns up to limit held deliveries that are due at now, either
+// because their release time has passed or because their recipient turned do-not-disturb off.
+func (r *DeliveryRepository) ReleaseHeldDeliveries(now time.Time, limit int) ([]*models.HeldDelivery, error) {
+	query := `
+	DELETE FROM held_deliveries
+	WHERE id IN (
+		SELECT h.id FROM held_deliveries h
+		JOIN users u ON u.id = h.recipient_id
+		WHERE h.release_at <= $1 OR (h.release_at IS NULL AND NOT u.do_not_disturb)
+		ORDER BY h.id
+		LIMIT $2
+		FOR UPDATE OF h SKIP LOCKED)
+	RETURNING id, notification_id, recipient_id, release_at, created_at`
+	results, err := r.db.Query(query, now.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		log.Println("Error releasing held deliveries:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	heldDeliveries := []*models.HeldDelivery{}
+	for results.Next() {
+		var heldDelivery models.HeldDelivery
+		err := results.Scan(&heldDelivery.ID, &heldDelivery.NotificationID, &heldDelivery.RecipientID, &heldDelivery.ReleaseAt, &heldDelivery.CreatedAt)
+		if err != nil {
+			log.Println("Error scanning held delivery row:", err)
+			return nil, err
+		}
+		heldDeliveries = append(heldDeliveries, &heldDelivery)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over held delivery rows:", err)
+		return nil, err
+	}
+	return heldDeliveries, nil
+}
+
+// ClaimDueJobs leases up to limit jobs that are due at now and counts the attempt, so that no other
+// instance runs them until the lease expires or the job is completed.
+func (r *DeliveryRepository) ClaimDueJobs(now time.Time, lease time.Duration, limit int) ([]*models.DeliveryJob, error) {
+	query := `
+	UPDATE delivery_jobs
+	SET locked_until = $2, attempts = attempts + 1, updated_at = $1
+	WHERE id IN (
+		SELECT id FROM delivery_jobs
+		WHERE run_at <= $1 AND (locked_until IS NULL OR locked_until < $1)
+		ORDER BY run_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED)
+	RETURNING ` + deliveryJobColumns
+	results, err := r.db.Query(query, now.UTC().Format(time.RFC3339), now.Add(lease).UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		log.Println("Error claiming due delivery jobs:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	return collectDeliveryJobs(results)
+}
+
+// CompleteJob removes a job that was delivered or can no longer be delivered.
+func (r *DeliveryRepository) CompleteJob(ID int64) error {
+	query := `DELETE FROM delivery_jobs WHERE id = ($1)`
+
+	_, err := r.db.Exec(query, ID)
+	if err != nil {
+		log.Println("Error completing delivery job:", err)
+	}
+	return err
+}
+
+// RetryJob records a failed attempt, schedules the next one at runAt and releases the lease.
+func (r *DeliveryRepository) RetryJob(ID int64, runAt time.Time, lastError string) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	UPDATE delivery_jobs
+	SET run_at = $1, last_error = $2, locked_until = NULL, updated_at = $3
+	WHERE id = $4`
+
+	_, err := r.db.Exec(query, runAt.UTC().Format(time.RFC3339), lastError, currentTime, ID)
+	if err != nil {
+		log.Println("Error rescheduling delivery job:", err)
+	}
+	return err
+}
+
+// DeadLetterJob moves a job that has used up its attempts to the dead-letter table.
+func (r *DeliveryRepository) DeadLetterJob(ID int64, lastError string) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	WITH failed AS (
+		DELETE FROM delivery_jobs WHERE id = $1
+		RETURNING id, channel, target_id, notification_id, recipient_id, attempts, created_at
+	)
+	INSERT INTO dead_letter_jobs(job_id, channel, target_id, notification_id, recipient_id, attempts, last_error, created_at, failed_at)
+	SELECT id, channel, target_id, notification_id, recipient_id, attempts, ($2)::TEXT, created_at, ($3)::TIMESTAMP WITH TIME ZONE
+	FROM failed`
+
+	_, err := r.db.Exec(query, ID, lastError, currentTime)
+	if err != nil {
+		log.Println("Error dead-lettering delivery job:", err)
+	}
+	return err
+}
+
+// GetJobs retrieves queued jobs with pagination. When failingOnly is true only jobs with a failed attempt are returned.
+func (r *DeliveryRepository) GetJobs(failingOnly bool, page, pageSize int) ([]*models.DeliveryJob, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+	query := `
+	SELECT ` + deliveryJobColumns + `
+	FROM delivery_jobs
+	WHERE NOT $1 OR last_error IS NOT NULL
+	ORDER BY run_at, id
+	LIMIT $2 OFFSET $3`
+	results, err := r.db.Query(query, failingOnly, pageSize, offset)
+	if err != nil {
+		log.Println("Error retrieving delivery jobs:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	return collectDeliveryJobs(results)
+}
+
+// GetDeadLetterJobByID retrieves a dead-lettered job by its ID from the database.
+func (r *DeliveryRepository) GetDeadLetterJobByID(ID int64) (*models.DeadLetterJob, error) {
+	query := `
+	SELECT ` + deadLetterJobColumns + `
+	FROM dead_letter_jobs WHERE id = ($1)`
+
+	result := r.db.QueryRow(query, ID)
+
+	var job models.DeadLetterJob
+	err := scanDeadLetterJob(result, &job)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error retrieving dead-letter job:", err)
+			return nil, utils.ErrNotFound
+		}
+		log.Println("Error retrieving dead-letter job:", err)
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetDeadLetterJobs retrieves dead-lettered jobs, most recent failures first, with pagination.
+func (r *DeliveryRepository) GetDeadLetterJobs(page, pageSize int) ([]*models.DeadLetterJob, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+	query := `
+	SELECT ` + deadLetterJobColumns + `
+	FROM dead_letter_jobs
+	ORDER BY failed_at DESC, id DESC
+	LIMIT $1 OFFSET $2`
+	results, err := r.db.Query(query, pageSize, offset)
+	if err != nil {
+		log.Println("Error retrieving dead-letter jobs:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	jobs := []*models.DeadLetterJob{}
+	for results.Next() {
+		var job models.DeadLetterJob
+		err := scanDeadLetterJob(results, &job)
+		if err != nil {
+			log.Println("Error scanning dead-letter job row:", err)
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over dead-letter job rows:", err)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ReplayDeadLetterJob moves a dead-lettered job back to the queue with a fresh set of attempts and returns the new job.
+func (r *DeliveryRepository) ReplayDeadLetterJob(ID int64, maxAttempts int) (*models.DeliveryJob, error) {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	WITH replayed AS (
+		DELETE FROM dead_letter_jobs WHERE id = $1
+		RETURNING channel, target_id, notification_id, recipient_id
+	)
+	INSERT INTO delivery_jobs(channel, target_id, notification_id, recipient_id, max_attempts, run_at, created_at, updated_at)
+	SELECT channel, target_id, notification_id, recipient_id, ($2)::INTEGER, ($3)::TIMESTAMP WITH TIME ZONE, ($3)::TIMESTAMP WITH TIME ZONE, ($3)::TIMESTAMP WITH TIME ZONE
+	FROM replayed
+	RETURNING ` + deliveryJobColumns
+
+	result := r.db.QueryRow(query, ID, maxAttempts, currentTime)
+
+	var job models.DeliveryJob
+	err := scanDeliveryJob(result, &job)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error replaying dead-letter job:", err)
+			return nil, utils.ErrNotFound
+		}
+		log.Println("Error replaying dead-letter job:", err)
+		return nil, err
+	}
+	return &job, nil
+}
+
+// DeleteDeadLetterJobByID discards a dead-lettered job.
+func (r *DeliveryRepository) DeleteDeadLetterJobByID(ID int64) error {
+	query := `DELETE FROM dead_letter_jobs WHERE id = ($1)`
+
+	result, err := r.db.Exec(query, ID)
+	if err != nil {
+		log.Println("Error deleting dead-letter job:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+func collectDeliveryJobs(results *sql.Rows) ([]*models.DeliveryJob, error) {
+	jobs := []*models.DeliveryJob{}
+	for results.Next() {
+		var job models.DeliveryJob
+		err := scanDeliveryJob(results, &job)
+		if err != nil {
+			log.Println("Error scanning delivery job row:", err)
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over delivery job rows:", err)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func scanDeliveryJob(row rowScanner, job *models.DeliveryJob) error {
+	return row.Scan(
+		&job.ID,
+		&job.Channel,
+		&job.TargetID,
+		&job.NotificationID,
+		&job.RecipientID,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&job.LastError,
+		&job.CreatedAt,
+		&job.UpdatedAt)
+}
+
+func scanDeadLetterJob(row rowScanner, job *models.DeadLetterJob) error {
+	return row.Scan(
+		&job.ID,
+		&job.JobID,
+		&job.Channel,
+		&job.TargetID,
+		&job.NotificationID,
+		&job.RecipientID,
+		&job.Attempts,
+		&job.LastError,
+		&job.CreatedAt,
+		&job.FailedAt)
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/lib/pq"
+)
+
+type DigestRepository struct {
+	db *sql.DB
+}
+
+func NewDigestRepository(db *sql.DB) *DigestRepository {
+	return &DigestRepository{
+		db: db,
+	}
+}
+
+// ClaimDueDigests leases up to limit users whose digest is due at now by moving their next digest
+// to the end of the lease, so that a digest that fails to send is retried once the lease expires.
+func (r *DigestRepository) ClaimDueDigests(now time.Time, lease time.Duration, limit int) ([]*models.DigestSubscriber, error) {
+	query := `
+	UPDATE users
+	SET digest_next_at = $2
+	WHERE id IN (
+		SELECT id FROM users
+		WHERE digest_frequency <> 'off' AND digest_next_at <= $1
+		ORDER BY digest_next_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED)
+	RETURNING id, digest_frequency, timezone`
+	results, err := r.db.Query(query, now.UTC().Format(time.RFC3339), now.Add(lease).UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		log.Println("Error claiming due digests:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	subscribers := []*models.DigestSubscriber{}
+	for results.Next() {
+		var subscriber models.DigestSubscriber
+		err := results.Scan(&subscriber.UserID, &subscriber.Frequency, &subscriber.Timezone)
+		if err != nil {
+			log.Println("Error scanning digest subscriber row:", err)
+			return nil, err
+		}
+		subscribers = append(subscribers, &subscriber)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over digest subscriber rows:", err)
+		return nil, err
+	}
+	return subscribers, nil
+}
+
+// GetDigestNotifications retrieves up to limit unread Low and Mid priority notifications dispatched to
+// a user since the given time that have not been part of an earlier digest.
+func (r *DigestRepository) GetDigestNotifications(userID int64, since time.Time, limit int) ([]*models.Notification, error) {
+	query := `
+	SELECT ` + notificationColumns + `
+	FROM notifications n
+	INNER JOIN notification_recipients nr ON nr.notification_id = n.id
+	WHERE nr.recipient_id = $1 AND nr.read_at IS NULL AND ` + notificationIsVisible + `
+	AND n.priority IN ($2, $3) AND n.dispatched_at >= $4
+	AND NOT EXISTS (SELECT 1 FROM digest_items di WHERE di.user_id = $1 AND di.notification_id = n.id)
+	ORDER BY n.dispatched_at ASC, n.id ASC
+	LIMIT $5`
+	results, err := r.db.Query(query, userID, models.Low, models.Mid, since.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		log.Println("Error retrieving digest notifications:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	notifications := []*models.Notification{}
+	for results.Next() {
+		var notification models.Notification
+		err := scanNotification(results, &notification)
+		if err != nil {
+			log.Println("Error scanning notification row:", err)
+			return nil, err
+		}
+		notifications = append(notifications, &notification)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over notification rows:", err)
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// CreateDigest records a digest and the notifications that went into it.
+func (r *DigestRepository) CreateDigest(digest *models.Digest) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	notificationIDs := make([]int64, len(digest.Notifications))
+	for i, notification := range digest.Notifications {
+		notificationIDs[i] = notification.ID
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO digests(
+		user_id,
+		frequency,
+		period_start,
+		period_end,
+		created_at
+	) VALUES (($1), ($2), ($3), ($4), ($5))
+	RETURNING id, created_at`
+
+	err = tx.QueryRow(query, digest.UserID, digest.Frequency, digest.PeriodStart, digest.PeriodEnd, currentTime).Scan(&digest.ID, &digest.CreatedAt)
+	if err != nil {
+		log.Println("Error inserting digest:", err)
+		return err
+	}
+
+	query = `
+	INSERT INTO digest_items(digest_id, user_id, notification_id)
+	SELECT ($1)::INTEGER, ($2)::INTEGER, notification_id
+	FROM unnest($3::INTEGER[]) AS notification_id
+	ON CONFLICT (user_id, notification_id) DO NOTHING`
+
+	_, err = tx.Exec(query, digest.ID, digest.UserID, pq.Array(notificationIDs))
+	if err != nil {
+		log.Println("Error inserting digest items:", err)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Println("Error committing digest:", err)
+	}
+	return err
+}
+
+// DeleteDigestByID removes a digest that could not be sent, so its notifications go into the next one.
+func (r *DigestRepository) DeleteDigestByID(ID int64) error {
+	query := `DELETE FROM digests WHERE id = ($1)`
+
+	_, err := r.db.Exec(query, ID)
+	if err != nil {
+		log.Println("Error deleting digest:", err)
+	}
+	return err
+}
+
+// ScheduleNextDigest sets when the next digest of a user is due.
+func (r *DigestRepository) ScheduleNextDigest(userID int64, nextAt time.Time) error {
+	query := `UPDATE users SET digest_next_at = $1 WHERE id = $2`
+
+	_, err := r.db.Exec(query, nextAt.UTC().Format(time.RFC3339), userID)
+	if err != nil {
+		log.Println("Error scheduling next digest:", err)
+	}
+	return err
+}
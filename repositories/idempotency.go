@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// idempotencyKeyColumns lists the columns read by scanIdempotencyKey.
+const idempotencyKeyColumns = `id, user_id, key, request_hash, response_status, response_headers, response_body, locked_until, created_at`
+
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db: db,
+	}
+}
+
+// ReserveIdempotencyKey records a request made at now with a key and leases the key to it until
+// lockedUntil. It takes over a key created before expiredBefore, or one whose request never
+// completed within its lease. When the key is already held it returns the existing record and false.
+func (r *IdempotencyRepository) ReserveIdempotencyKey(userID int64, key, requestHash string, now, expiredBefore, lockedUntil time.Time) (*models.IdempotencyKey, bool, error) {
+	currentTime := now.UTC().Format(time.RFC3339)
+
+	query := `
+	INSERT INTO idempotency_keys(
+		user_id,
+		key,
+		request_hash,
+		locked_until,
+		created_at
+	) VALUES (($1), ($2), ($3), ($6), ($4))
+	ON CONFLICT (user_id, key) DO UPDATE
+	SET request_hash = EXCLUDED.request_hash,
+		response_status = NULL,
+		response_headers = NULL,
+		response_body = NULL,
+		locked_until = EXCLUDED.locked_until,
+		created_at = EXCLUDED.created_at
+	WHERE idempotency_keys.created_at < ($5)
+		OR (idempotency_keys.response_status IS NULL AND idempotency_keys.locked_until < ($4))
+	RETURNING ` + idempotencyKeyColumns
+
+	result := r.db.QueryRow(query, userID, key, requestHash, currentTime, expiredBefore.UTC().Format(time.RFC3339),
+		lockedUntil.UTC().Format(time.RFC3339))
+
+	var idempotencyKey models.IdempotencyKey
+	err := scanIdempotencyKey(result, &idempotencyKey)
+	if err == nil {
+		return &idempotencyKey, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		log.Println("Error reserving idempotency key:", err)
+		return nil, false, err
+	}
+
+	// The key is held by an earlier request
+	query = `
+	SELECT ` + idempotencyKeyColumns + `
+	FROM idempotency_keys WHERE user_id = ($1) AND key = ($2)`
+
+	result = r.db.QueryRow(query, userID, key)
+
+	err = scanIdempotencyKey(result, &idempotencyKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error retrieving idempotency key:", err)
+			return nil, false, utils.ErrNotFound
+		}
+		log.Println("Error retrieving idempotency key:", err)
+		return nil, false, err
+	}
+	return &idempotencyKey, false, nil
+}
+
+// SaveIdempotentResponse stores the response of the request made with an idempotency key, unless
+// the lease ending at lockedUntil was lost to a retry. It returns ErrNotFound when it was.
+func (r *IdempotencyRepository) SaveIdempotentResponse(ID int64, lockedUntil *string, status int, headers http.Header, body string) error {
+	encodedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		log.Println("Error encoding response headers:", err)
+		return err
+	}
+
+	query := `
+	UPDATE idempotency_keys
+	SET response_status = ($1), response_headers = ($2), response_body = ($3)
+	WHERE id = ($4) AND locked_until = ($5)`
+
+	result, err := r.db.Exec(query, status, encodedHeaders, body, ID, lockedUntil)
+	if err != nil {
+		log.Println("Error saving idempotent response:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+// DeleteIdempotencyKeyByID releases a key, so that the request can be retried with it. A key whose
+// lease ending at lockedUntil was lost to a retry is left to that retry.
+func (r *IdempotencyRepository) DeleteIdempotencyKeyByID(ID int64, lockedUntil *string) error {
+	query := `DELETE FROM idempotency_keys WHERE id = ($1) AND locked_until = ($2)`
+
+	_, err := r.db.Exec(query, ID, lockedUntil)
+	if err != nil {
+		log.Println("Error deleting idempotency key:", err)
+	}
+	return err
+}
+
+// DeleteExpiredIdempotencyKeys removes the keys created before the given time and returns how many were removed.
+func (r *IdempotencyRepository) DeleteExpiredIdempotencyKeys(before time.Time) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE created_at < ($1)`
+
+	result, err := r.db.Exec(query, before.UTC().Format(time.RFC3339))
+	if err != nil {
+		log.Println("Error deleting expired idempotency keys:", err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanIdempotencyKey(row rowScanner, idempotencyKey *models.IdempotencyKey) error {
+	var headers []byte
+	err := row.Scan(
+		&idempotencyKey.ID,
+		&idempotencyKey.UserID,
+		&idempotencyKey.Key,
+		&idempotencyKey.RequestHash,
+		&idempotencyKey.ResponseStatus,
+		&headers,
+		&idempotencyKey.ResponseBody,
+		&idempotencyKey.LockedUntil,
+		&idempotencyKey.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if headers != nil {
+		return json.Unmarshal(headers, &idempotencyKey.ResponseHeaders)
+	}
+	return nil
+}
@@ -3,14 +3,43 @@ package repositories
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/akinolaemmanuel49/notify-api/models"
 	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/lib/pq"
 )
 
+// notificationColumns lists the columns read by scanNotification, using the alias n for the notifications table.
+const notificationColumns = `n.id, n.title, n.message, n.priority, n.publisher_id, COALESCE(n.topic, ''), n.send_at, n.expires_at, n.translations, n.created_at, n.updated_at`
+
+// notificationIsUnexpired restricts a query to notifications that have not passed their expires_at time.
+const notificationIsUnexpired = `(n.expires_at IS NULL OR n.expires_at > NOW())`
+
+// notificationIsVisible restricts a query to notifications that are no longer waiting for their send_at time
+// and have not expired yet.
+const notificationIsVisible = `n.dispatched_at IS NOT NULL AND ` + notificationIsUnexpired
+
+// updatableNotificationColumns lists the columns UpdateNotificationByID lets publishers set.
+var updatableNotificationColumns = map[string]bool{
+	"title":        true,
+	"message":      true,
+	"priority":     true,
+	"send_at":      true,
+	"expires_at":   true,
+	"translations": true,
+}
+
+// notificationIsAccessible restricts a query to notifications the user given as $1 published, is a
+// recipient of or subscribes to the topic of.
+const notificationIsAccessible = `(n.publisher_id = $1
+	OR EXISTS (SELECT 1 FROM notification_recipients nr WHERE nr.notification_id = n.id AND nr.recipient_id = $1)
+	OR EXISTS (SELECT 1 FROM subscriptions s INNER JOIN topics t ON t.id = s.topic_id WHERE t.name = n.topic AND s.user_id = $1))`
+
 type NotificationRepository struct {
 	db *sql.DB
 }
@@ -21,60 +50,181 @@ func NewNotificationRepository(db *sql.DB) *NotificationRepository {
 	}
 }
 
-// CreateNotification creates a new instance of NotificationRepository.
-func (r *NotificationRepository) CreateNotification(notificationInput *models.NotificationInput, publisherID int64) error {
-	currentTime := time.Now().UTC().Format(time.RFC3339)
+// CreateNotification stores a new notification created at now and returns it as created.
+// Notifications with a send_at time after now are stored unreleased until the scheduler picks them up.
+func (r *NotificationRepository) CreateNotification(notificationInput *models.NotificationInput, publisherID int64, now time.Time) (*models.Notification, error) {
+	now = now.UTC()
+	currentTime := now.Format(time.RFC3339)
+
+	// Immediate notifications are released as soon as they are created
+	var sendAt, dispatchedAt, expiresAt *string
+	if notificationInput.IsScheduled(now) {
+		scheduledTime := notificationInput.SendAt.UTC().Format(time.RFC3339)
+		sendAt = &scheduledTime
+	} else {
+		dispatchedAt = &currentTime
+	}
+	if expiry := notificationInput.Expiry(now); expiry != nil {
+		expiryTime := expiry.UTC().Format(time.RFC3339)
+		expiresAt = &expiryTime
+	}
 
 	notification := models.Notification{
-		Title:       notificationInput.Title,
-		Message:     notificationInput.Message,
-		Priority:    notificationInput.Priority,
-		PublisherID: publisherID,
-		CreatedAt:   currentTime,
-		UpdatedAt:   currentTime,
+		Title:        notificationInput.Title,
+		Message:      notificationInput.Message,
+		Priority:     notificationInput.Priority,
+		PublisherID:  publisherID,
+		Topic:        notificationInput.Topic,
+		Translations: notificationInput.Translations,
+		CreatedAt:    currentTime,
+		UpdatedAt:    currentTime,
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Publishing to a topic that does not exist yet creates it
+	if notification.Topic != "" {
+		query := `
+		INSERT INTO topics(
+			name,
+			created_by,
+			created_at,
+			updated_at)
+		VALUES (($1), ($2), ($3), ($3))
+		ON CONFLICT (name) DO NOTHING`
+
+		_, err = tx.Exec(query, notification.Topic, notification.PublisherID, currentTime)
+		if err != nil {
+			log.Println("Error creating topic:", err)
+			return nil, err
+		}
 	}
 
 	query := `
-	INSERT INTO notifications(
+	INSERT INTO notifications AS n (
 		title,
 		message,
 		priority,
 		publisher_id,
+		topic,
+		send_at,
+		dispatched_at,
+		expires_at,
+		translations,
 		created_at,
 		updated_at) 
-	VALUES (($1), ($2), ($3), ($4), ($5), ($6))`
+	VALUES (($1), ($2), ($3), ($4), NULLIF($5, ''), ($6), ($7), ($8), ($9), ($10), ($11))
+	RETURNING ` + notificationColumns
 
-	_, err := r.db.Exec(query,
+	result := tx.QueryRow(query,
 		notification.Title,
 		notification.Message,
 		notification.Priority,
 		notification.PublisherID,
+		notification.Topic,
+		sendAt,
+		dispatchedAt,
+		expiresAt,
+		notification.Translations,
 		notification.CreatedAt,
 		notification.UpdatedAt)
 
+	err = scanNotification(result, &notification)
 	if err != nil {
 		log.Println("Error inserting notification:", err)
+		return nil, err
 	}
-	return err
+
+	if len(notificationInput.Recipients) > 0 {
+		query = `
+		INSERT INTO notification_recipients(
+			notification_id,
+			recipient_id,
+			created_at)
+		SELECT ($1)::INTEGER, recipient_id, ($3)::TIMESTAMP WITH TIME ZONE
+		FROM unnest(($2)::INTEGER[]) AS recipient_id
+		ON CONFLICT DO NOTHING`
+
+		_, err = tx.Exec(query, notification.ID, pq.Array(notificationInput.Recipients), currentTime)
+		if err != nil {
+			if pgErr, ok := err.(*pq.Error); ok {
+				if pgErr.Code == "23503" {
+					return nil, utils.ErrRecipientNotFound
+				}
+			}
+			log.Println("Error inserting notification recipients:", err)
+			return nil, err
+		}
+	}
+
+	// Fan the notification out to every subscriber of its topic
+	if notification.Topic != "" {
+		query = `
+		INSERT INTO notification_recipients(
+			notification_id,
+			recipient_id,
+			created_at)
+		SELECT ($1)::INTEGER, s.user_id, ($3)::TIMESTAMP WITH TIME ZONE
+		FROM subscriptions s
+		INNER JOIN topics t ON t.id = s.topic_id
+		WHERE t.name = ($2)
+		ON CONFLICT DO NOTHING`
+
+		_, err = tx.Exec(query, notification.ID, notification.Topic, currentTime)
+		if err != nil {
+			log.Println("Error fanning out notification to subscribers:", err)
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Println("Error committing notification:", err)
+		return nil, err
+	}
+	return &notification, nil
 }
 
 // GetNotificationByID retrieves a notification by its ID from the database.
 func (r *NotificationRepository) GetNotificationByID(ID int64) (*models.Notification, error) {
 	query := `
-	SELECT 
-		id, 
-		title, 
-		message, 
-		priority, 
-		publisher_id, 
-		created_at, 
-		updated_at 
-	FROM notifications WHERE id = ($1)`
+	SELECT ` + notificationColumns + `
+	FROM notifications n WHERE n.id = ($1)`
 
 	result := r.db.QueryRow(query, ID)
 
 	var notification models.Notification
-	err := result.Scan(&notification.ID, &notification.Title, &notification.Message, &notification.Priority, &notification.PublisherID, &notification.CreatedAt, &notification.UpdatedAt)
+	err := scanNotification(result, &notification)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error retrieving notification:", err)
+			return nil, utils.ErrNotFound
+		}
+		log.Println("Error retrieving notification:", err)
+		return nil, err
+	}
+	log.Println("Retrieving notification with ID: ", ID)
+	return &notification, nil
+}
+
+// GetAccessibleNotificationByID retrieves a notification by its ID when the given user may read it.
+// Notifications the user may not read, expired notifications and, unless the user published them,
+// notifications waiting for their send_at time are reported as not found.
+func (r *NotificationRepository) GetAccessibleNotificationByID(ID, userID int64) (*models.Notification, error) {
+	query := `
+	SELECT ` + notificationColumns + `
+	FROM notifications n WHERE n.id = $2 AND ` + notificationIsAccessible + ` AND ` + notificationIsUnexpired + `
+	AND (n.publisher_id = $1 OR n.dispatched_at IS NOT NULL)`
+
+	result := r.db.QueryRow(query, userID, ID)
+
+	var notification models.Notification
+	err := scanNotification(result, &notification)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			log.Println("Error retrieving notification:", err)
@@ -94,8 +244,8 @@ func (r *NotificationRepository) GetOwnNotifications(ID int64, page, pageSize in
 	}
 	offset := (page - 1) * pageSize
 	query := `
-	SELECT id, title, message, priority, publisher_id, created_at, updated_at 
-	FROM notifications WHERE publisher_id = $1 
+	SELECT ` + notificationColumns + `
+	FROM notifications n WHERE n.publisher_id = $1 AND ` + notificationIsUnexpired + `
 	LIMIT $2 OFFSET $3`
 	results, err := r.db.Query(query, ID, pageSize, offset)
 	if err != nil {
@@ -107,7 +257,7 @@ func (r *NotificationRepository) GetOwnNotifications(ID int64, page, pageSize in
 	notifications := []*models.Notification{}
 	for results.Next() {
 		var notification models.Notification
-		err := results.Scan(&notification.ID, &notification.Title, &notification.Message, &notification.Priority, &notification.PublisherID, &notification.CreatedAt, &notification.UpdatedAt)
+		err := scanNotification(results, &notification)
 		if err != nil {
 			log.Println("Error scanning notification row:", err)
 			return nil, err
@@ -122,15 +272,322 @@ func (r *NotificationRepository) GetOwnNotifications(ID int64, page, pageSize in
 	return notifications, nil
 }
 
-// GetAllNotifications retrieves all notifications from the database with pagination.
-func (r *NotificationRepository) GetAllNotifications(page, pageSize int) ([]*models.Notification, error) {
+// GetInboxNotifications retrieves all notifications addressed to a specific recipient with pagination.
+// Notifications that have not been delivered before are marked as delivered.
+func (r *NotificationRepository) GetInboxNotifications(recipientID int64, unreadOnly bool, page, pageSize int) ([]*models.InboxNotification, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+	query := `
+	SELECT ` + notificationColumns + `,
+		nr.delivered_at, nr.read_at, nr.acknowledged_at
+	FROM notifications n
+	INNER JOIN notification_recipients nr ON nr.notification_id = n.id
+	WHERE nr.recipient_id = $1 AND ` + notificationIsVisible + ` AND ($2 = FALSE OR nr.read_at IS NULL)
+	ORDER BY n.created_at DESC
+	LIMIT $3 OFFSET $4`
+	results, err := r.db.Query(query, recipientID, unreadOnly, pageSize, offset)
+	if err != nil {
+		log.Println("Error retrieving inbox notifications:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	notifications := []*models.InboxNotification{}
+	undelivered := []int64{}
+	for results.Next() {
+		var notification models.InboxNotification
+		err := scanNotification(results, &notification.Notification, &notification.DeliveredAt, &notification.ReadAt, &notification.AcknowledgedAt)
+		if err != nil {
+			log.Println("Error scanning notification row:", err)
+			return nil, err
+		}
+		if notification.DeliveredAt == nil {
+			undelivered = append(undelivered, notification.ID)
+		}
+		notifications = append(notifications, &notification)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over notification rows:", err)
+		return nil, err
+	}
+
+	if len(undelivered) > 0 {
+		deliveredAt := time.Now().UTC().Format(time.RFC3339)
+		err = r.MarkNotificationsAsDelivered(recipientID, undelivered, deliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		for _, notification := range notifications {
+			if notification.DeliveredAt == nil {
+				notification.DeliveredAt = &deliveredAt
+			}
+		}
+	}
+	log.Println("Retrieving inbox notifications")
+	return notifications, nil
+}
+
+// GetInboxNotificationsAfter retrieves up to limit notifications addressed to a recipient that were released
+// after the notification with ID afterID, in the order they were released.
+// Scheduled notifications are released after notifications created later, so IDs alone do not give release order.
+func (r *NotificationRepository) GetInboxNotificationsAfter(recipientID, afterID int64, limit int) ([]*models.Notification, error) {
+	query := `
+	WITH last_event AS (
+		SELECT dispatched_at, id FROM notifications WHERE id = $2 AND dispatched_at IS NOT NULL
+	)
+	SELECT ` + notificationColumns + `
+	FROM notifications n
+	INNER JOIN notification_recipients nr ON nr.notification_id = n.id
+	WHERE nr.recipient_id = $1 AND ` + notificationIsVisible + `
+	AND CASE
+		WHEN EXISTS (SELECT 1 FROM last_event) THEN (n.dispatched_at, n.id) > (SELECT dispatched_at, id FROM last_event)
+		ELSE n.id > $2
+	END
+	ORDER BY n.dispatched_at ASC, n.id ASC
+	LIMIT $3`
+	results, err := r.db.Query(query, recipientID, afterID, limit)
+	if err != nil {
+		log.Println("Error retrieving missed notifications:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	notifications := []*models.Notification{}
+	for results.Next() {
+		var notification models.Notification
+		err := scanNotification(results, &notification)
+		if err != nil {
+			log.Println("Error scanning notification row:", err)
+			return nil, err
+		}
+		notifications = append(notifications, &notification)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over notification rows:", err)
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// GetRecipientIDs retrieves the IDs of every recipient of a notification.
+func (r *NotificationRepository) GetRecipientIDs(ID int64) ([]int64, error) {
+	query := `
+	SELECT recipient_id FROM notification_recipients WHERE notification_id = $1`
+	results, err := r.db.Query(query, ID)
+	if err != nil {
+		log.Println("Error retrieving notification recipients:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	recipientIDs := []int64{}
+	for results.Next() {
+		var recipientID int64
+		if err := results.Scan(&recipientID); err != nil {
+			log.Println("Error scanning recipient row:", err)
+			return nil, err
+		}
+		recipientIDs = append(recipientIDs, recipientID)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over recipient rows:", err)
+		return nil, err
+	}
+	return recipientIDs, nil
+}
+
+// ReleaseDueNotifications marks up to limit scheduled notifications whose send_at time has passed as released
+// and returns their IDs. Rows locked by another instance are skipped.
+func (r *NotificationRepository) ReleaseDueNotifications(limit int) ([]int64, error) {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	UPDATE notifications
+	SET dispatched_at = $1
+	WHERE id IN (
+		SELECT id FROM notifications
+		WHERE dispatched_at IS NULL AND send_at <= $1 AND (expires_at IS NULL OR expires_at > $1)
+		ORDER BY send_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED)
+	RETURNING id`
+	results, err := r.db.Query(query, currentTime, limit)
+	if err != nil {
+		log.Println("Error releasing scheduled notifications:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	IDs := []int64{}
+	for results.Next() {
+		var ID int64
+		if err := results.Scan(&ID); err != nil {
+			log.Println("Error scanning released notification row:", err)
+			return nil, err
+		}
+		IDs = append(IDs, ID)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over released notification rows:", err)
+		return nil, err
+	}
+	return IDs, nil
+}
+
+// PurgeExpiredNotifications removes up to limit expired notifications and returns how many were removed.
+// When archive is true the notifications are moved to archived_notifications instead of being deleted.
+func (r *NotificationRepository) PurgeExpiredNotifications(archive bool, limit int) (int64, error) {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	DELETE FROM notifications
+	WHERE id IN (
+		SELECT id FROM notifications
+		WHERE expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED)`
+	if archive {
+		query = `
+		WITH expired AS (
+			DELETE FROM notifications
+			WHERE id IN (
+				SELECT id FROM notifications
+				WHERE expires_at <= $1
+				ORDER BY expires_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED)
+			RETURNING id, title, message, priority, publisher_id, topic, send_at, dispatched_at, expires_at, translations, created_at, updated_at
+		)
+		INSERT INTO archived_notifications(
+			id,
+			title,
+			message,
+			priority,
+			publisher_id,
+			topic,
+			send_at,
+			dispatched_at,
+			expires_at,
+			translations,
+			created_at,
+			updated_at,
+			archived_at)
+		SELECT id, title, message, priority, publisher_id, topic, send_at, dispatched_at, expires_at, translations, created_at, updated_at, ($1)::TIMESTAMP WITH TIME ZONE
+		FROM expired`
+	}
+
+	result, err := r.db.Exec(query, currentTime, limit)
+	if err != nil {
+		log.Println("Error purging expired notifications:", err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// MarkNotificationsAsDelivered records the first delivery of notifications to a recipient.
+func (r *NotificationRepository) MarkNotificationsAsDelivered(recipientID int64, notificationIDs []int64, deliveredAt string) error {
+	query := `
+	UPDATE notification_recipients
+	SET delivered_at = $1
+	WHERE recipient_id = $2 AND notification_id = ANY($3) AND delivered_at IS NULL`
+
+	_, err := r.db.Exec(query, deliveredAt, recipientID, pq.Array(notificationIDs))
+	if err != nil {
+		log.Println("Error marking notifications as delivered:", err)
+	}
+	return err
+}
+
+// MarkNotificationAsRead records that a recipient has read a visible notification.
+func (r *NotificationRepository) MarkNotificationAsRead(ID, recipientID int64) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	UPDATE notification_recipients
+	SET delivered_at = COALESCE(delivered_at, $1),
+		read_at = COALESCE(read_at, $1)
+	WHERE notification_id = $2 AND recipient_id = $3
+	AND notification_id IN (SELECT n.id FROM notifications n WHERE ` + notificationIsVisible + `)`
+
+	result, err := r.db.Exec(query, currentTime, ID, recipientID)
+	if err != nil {
+		log.Println("Error marking notification as read:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+// AcknowledgeNotification records that a recipient has acknowledged a visible notification.
+// Acknowledging a notification also marks it as read.
+func (r *NotificationRepository) AcknowledgeNotification(ID, recipientID int64) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	UPDATE notification_recipients
+	SET delivered_at = COALESCE(delivered_at, $1),
+		read_at = COALESCE(read_at, $1),
+		acknowledged_at = COALESCE(acknowledged_at, $1)
+	WHERE notification_id = $2 AND recipient_id = $3
+	AND notification_id IN (SELECT n.id FROM notifications n WHERE ` + notificationIsVisible + `)`
+
+	result, err := r.db.Exec(query, currentTime, ID, recipientID)
+	if err != nil {
+		log.Println("Error acknowledging notification:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+// MarkAllNotificationsAsRead marks every unread notification of a recipient as read and returns how many were updated.
+func (r *NotificationRepository) MarkAllNotificationsAsRead(recipientID int64) (int64, error) {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	UPDATE notification_recipients
+	SET delivered_at = COALESCE(delivered_at, $1),
+		read_at = $1
+	WHERE recipient_id = $2 AND read_at IS NULL
+	AND notification_id IN (SELECT n.id FROM notifications n WHERE ` + notificationIsVisible + `)`
+
+	result, err := r.db.Exec(query, currentTime, recipientID)
+	if err != nil {
+		log.Println("Error marking notifications as read:", err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetUnreadCount returns the number of unread notifications addressed to a recipient.
+func (r *NotificationRepository) GetUnreadCount(recipientID int64) (int64, error) {
+	query := `
+	SELECT COUNT(*)
+	FROM notification_recipients nr
+	INNER JOIN notifications n ON n.id = nr.notification_id
+	WHERE nr.recipient_id = $1 AND nr.read_at IS NULL AND ` + notificationIsVisible
+
+	var count int64
+	err := r.db.QueryRow(query, recipientID).Scan(&count)
+	if err != nil {
+		log.Println("Error counting unread notifications:", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllNotifications retrieves all notifications the given user may read with pagination.
+func (r *NotificationRepository) GetAllNotifications(userID int64, page, pageSize int) ([]*models.Notification, error) {
 	if page < 1 {
 		page = 1
 	}
 	offset := (page - 1) * pageSize
 	query := `
-	SELECT id, title, message, priority, publisher_id, created_at, updated_at FROM notifications LIMIT $1 OFFSET $2`
-	results, err := r.db.Query(query, pageSize, offset)
+	SELECT ` + notificationColumns + `
+	FROM notifications n WHERE ` + notificationIsAccessible + ` AND ` + notificationIsVisible + `
+	LIMIT $2 OFFSET $3`
+	results, err := r.db.Query(query, userID, pageSize, offset)
 	if err != nil {
 		log.Println("Error retrieving notifications:", err)
 		return nil, err
@@ -140,7 +597,7 @@ func (r *NotificationRepository) GetAllNotifications(page, pageSize int) ([]*mod
 	notifications := []*models.Notification{}
 	for results.Next() {
 		var notification models.Notification
-		err := results.Scan(&notification.ID, &notification.Title, &notification.Message, &notification.Priority, &notification.PublisherID, &notification.CreatedAt, &notification.UpdatedAt)
+		err := scanNotification(results, &notification)
 		if err != nil {
 			log.Println("Error scanning notification row:", err)
 			return nil, err
@@ -155,35 +612,54 @@ func (r *NotificationRepository) GetAllNotifications(page, pageSize int) ([]*mod
 	return notifications, nil
 }
 
+// GetNotificationSchedule retrieves the send_at and expires_at times of a notification of a
+// publisher, which are nil when not set, and whether it has been dispatched.
+func (r *NotificationRepository) GetNotificationSchedule(ID, publisherID int64) (*time.Time, *time.Time, bool, error) {
+	query := `
+	SELECT send_at, expires_at, dispatched_at IS NOT NULL
+	FROM notifications WHERE id = ($1) AND publisher_id = ($2)`
+
+	var sendAt, expiresAt *time.Time
+	var dispatched bool
+	err := r.db.QueryRow(query, ID, publisherID).Scan(&sendAt, &expiresAt, &dispatched)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, false, utils.ErrNotFound
+		}
+		log.Println("Error retrieving notification schedule:", err)
+		return nil, nil, false, err
+	}
+	return sendAt, expiresAt, dispatched, nil
+}
+
+// UpdateNotificationByID sets the given columns of a notification, which must all be listed in
+// updatableNotificationColumns; any other key is rejected with ErrUnknownNotificationField.
 func (r *NotificationRepository) UpdateNotificationByID(ID, publisherID int64, fields map[string]interface{}) error {
+	for key := range fields {
+		if !updatableNotificationColumns[key] {
+			return fmt.Errorf("%w: %s", utils.ErrUnknownNotificationField, key)
+		}
+	}
+
 	_, err := r.GetNotificationByID(ID)
 	if errors.Is(err, utils.ErrNotFound) {
 		log.Println("Error retrieving notification:", err)
 		return utils.ErrNotFound
 	}
 
-	query := "UPDATE notifications SET "
-
+	var assignments []string
 	var params []interface{}
-	i := 1
 	for key, value := range fields {
-		if key == "created_at" || key == "updated_at" || key == "publisher_id" {
-			continue
-		}
-		if i > 1 {
-			query += ", "
-		}
-		query += key + " = $" + strconv.Itoa(i)
 		params = append(params, value)
-		i++
+		assignments = append(assignments, key+" = $"+strconv.Itoa(len(params)))
 	}
 
-	query += ", updated_at = $" + strconv.Itoa(i)
-	query += " WHERE id = $" + strconv.Itoa(i+1)
-	query += " AND publisher_id = $" + strconv.Itoa(i+2)
-
 	updatedAt := time.Now().UTC().Format(time.RFC3339)
 	params = append(params, updatedAt, ID, publisherID)
+	assignments = append(assignments, "updated_at = $"+strconv.Itoa(len(params)-2))
+
+	query := "UPDATE notifications SET " + strings.Join(assignments, ", ") +
+		" WHERE id = $" + strconv.Itoa(len(params)-1) + " AND publisher_id = $" + strconv.Itoa(len(params))
 
 	_, err = r.db.Exec(query, params...)
 	if err != nil {
@@ -208,3 +684,38 @@ func (r *NotificationRepository) DeleteNotificationByID(ID, publisherID int64) e
 	}
 	return err
 }
+
+// requireAffectedRows returns utils.ErrNotFound when a statement did not touch any row.
+func requireAffectedRows(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return utils.ErrNotFound
+	}
+	return nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanNotification scans the notificationColumns of a row into notification, followed by any extra columns.
+func scanNotification(row rowScanner, notification *models.Notification, extra ...interface{}) error {
+	dest := []interface{}{
+		&notification.ID,
+		&notification.Title,
+		&notification.Message,
+		&notification.Priority,
+		&notification.PublisherID,
+		&notification.Topic,
+		&notification.SendAt,
+		&notification.ExpiresAt,
+		&notification.Translations,
+		&notification.CreatedAt,
+		&notification.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
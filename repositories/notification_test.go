@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+func TestUpdateNotificationByIDRejectsUnknownFields(t *testing.T) {
+	// Fields are checked before the database is touched
+	r := NewNotificationRepository(nil)
+
+	for _, key := range []string{
+		"dispatched_at",
+		"DISPATCHED_AT",
+		"publisher_id",
+		"topic",
+		"created_at",
+		"title = 'x', dispatched_at",
+	} {
+		err := r.UpdateNotificationByID(1, 1, map[string]interface{}{"title": "Hello", key: nil})
+		if !errors.Is(err, utils.ErrUnknownNotificationField) {
+			t.Errorf("UpdateNotificationByID with %q: got error %v, want ErrUnknownNotificationField", key, err)
+		}
+	}
+}
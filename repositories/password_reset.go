@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+type PasswordResetRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: db,
+	}
+}
+
+// CreatePasswordResetToken stores the hash of a new password reset token for a user. Tokens sent
+// earlier stop working, so only the most recent email can be used.
+func (r *PasswordResetRepository) CreatePasswordResetToken(userID int64, tokenHash string, expiresAt time.Time) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM password_reset_tokens WHERE user_id = ($1)`
+
+	_, err = tx.Exec(query, userID)
+	if err != nil {
+		log.Println("Error deleting password reset tokens:", err)
+		return err
+	}
+
+	query = `
+	INSERT INTO password_reset_tokens(
+		user_id,
+		token_hash,
+		expires_at,
+		created_at
+	) VALUES (($1), ($2), ($3), ($4))`
+
+	_, err = tx.Exec(query, userID, tokenHash, expiresAt.UTC().Format(time.RFC3339), currentTime)
+	if err != nil {
+		log.Println("Error inserting password reset token:", err)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Println("Error committing password reset token:", err)
+	}
+	return err
+}
+
+// ResetPassword uses up a password reset token, replaces the password hash of its user and revokes
+// the tokens and sessions issued to the user before. The reset also proves that the user can read mail sent to
+// their address, so the address is verified. It returns the ID of the user.
+func (r *PasswordResetRepository) ResetPassword(tokenHash, passwordHash string, now time.Time) (int64, error) {
+	currentTime := now.UTC().Format(time.RFC3339)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Deleting the token makes it single-use, even when two requests race to reset
+	query := `
+	DELETE FROM password_reset_tokens
+	WHERE token_hash = ($1) AND expires_at > ($2)
+	RETURNING user_id`
+
+	var userID int64
+	err = tx.QueryRow(query, tokenHash, currentTime).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, utils.ErrInvalidPasswordResetToken
+		}
+		log.Println("Error using password reset token:", err)
+		return 0, err
+	}
+
+	query = `
+	WITH revoked_families AS (
+		UPDATE token_families SET revoked_at = ($2) WHERE user_id = ($3) AND revoked_at IS NULL)
+	UPDATE users
+	SET password_hash = ($1), email_verified_at = COALESCE(email_verified_at, ($2)), tokens_valid_after = ($2), updated_at = ($2)
+	WHERE id = ($3)`
+
+	_, err = tx.Exec(query, passwordHash, currentTime, userID)
+	if err != nil {
+		log.Println("Error resetting password:", err)
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Println("Error committing password reset:", err)
+		return 0, err
+	}
+	return userID, nil
+}
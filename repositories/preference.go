@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/lib/pq"
+)
+
+type PreferenceRepository struct {
+	db *sql.DB
+}
+
+func NewPreferenceRepository(db *sql.DB) *PreferenceRepository {
+	return &PreferenceRepository{
+		db: db,
+	}
+}
+
+// GetPreferences retrieves the preferences of a user. Users who never saved any have no rules.
+func (r *PreferenceRepository) GetPreferences(userID int64) (*models.Preferences, error) {
+	query := `
+	SELECT priorities, topics, updated_at
+	FROM user_preferences
+	WHERE user_id = ($1)`
+
+	var priorities, topics []byte
+	preferences := models.Preferences{}
+	err := r.db.QueryRow(query, userID).Scan(&priorities, &topics, &preferences.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.Preferences{Priorities: map[string][]string{}, Topics: map[string][]string{}}, nil
+		}
+		log.Println("Error retrieving preferences:", err)
+		return nil, err
+	}
+	if err := decodeRules(priorities, topics, &preferences); err != nil {
+		log.Println("Error decoding preferences:", err)
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+// SavePreferences replaces the preferences of a user.
+func (r *PreferenceRepository) SavePreferences(userID int64, preferences *models.Preferences) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	priorities, err := json.Marshal(preferences.Priorities)
+	if err != nil {
+		return err
+	}
+	topics, err := json.Marshal(preferences.Topics)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO user_preferences(
+		user_id,
+		priorities,
+		topics,
+		created_at,
+		updated_at
+	) VALUES (($1), ($2), ($3), ($4), ($5))
+	ON CONFLICT (user_id) DO UPDATE
+	SET priorities = EXCLUDED.priorities, topics = EXCLUDED.topics, updated_at = EXCLUDED.updated_at`
+
+	_, err = r.db.Exec(query, userID, priorities, topics, currentTime, currentTime)
+	if err != nil {
+		log.Println("Error saving preferences:", err)
+	}
+	return err
+}
+
+// GetRecipientPreferences retrieves the routing preferences of every given user that exists.
+func (r *PreferenceRepository) GetRecipientPreferences(userIDs []int64) (map[int64]*models.RecipientPreferences, error) {
+	query := `
+	SELECT u.id, u.email_notifications, COALESCE(p.priorities, '{}'), COALESCE(p.topics, '{}'),
+	u.digest_frequency, u.timezone, u.quiet_hours_start, u.quiet_hours_end, u.do_not_disturb, u.high_priority_breakthrough
+	FROM users u
+	LEFT JOIN user_preferences p ON p.user_id = u.id
+	WHERE u.id = ANY($1)`
+	results, err := r.db.Query(query, pq.Array(userIDs))
+	if err != nil {
+		log.Println("Error retrieving recipient preferences:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	preferences := map[int64]*models.RecipientPreferences{}
+	for results.Next() {
+		var userID int64
+		var priorities, topics []byte
+		var recipientPreferences models.RecipientPreferences
+		quietHours := &recipientPreferences.QuietHours
+		err := results.Scan(&userID, &recipientPreferences.EmailNotifications, &priorities, &topics, &recipientPreferences.DigestFrequency,
+			&quietHours.Timezone, &quietHours.Start, &quietHours.End, &quietHours.DoNotDisturb, &quietHours.HighPriorityBreakthrough)
+		if err != nil {
+			log.Println("Error scanning recipient preferences row:", err)
+			return nil, err
+		}
+		if err := decodeRules(priorities, topics, &recipientPreferences.Preferences); err != nil {
+			log.Println("Error decoding preferences:", err)
+			return nil, err
+		}
+		preferences[userID] = &recipientPreferences
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over recipient preferences rows:", err)
+		return nil, err
+	}
+	return preferences, nil
+}
+
+func decodeRules(priorities, topics []byte, preferences *models.Preferences) error {
+	if err := json.Unmarshal(priorities, &preferences.Priorities); err != nil {
+		return err
+	}
+	return json.Unmarshal(topics, &preferences.Topics)
+}
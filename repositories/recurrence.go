@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/lib/pq"
+)
+
+// recurrenceColumns lists the columns read by scanRecurrence.
+const recurrenceColumns = `id, publisher_id, title, message, priority, COALESCE(topic, ''), recipients, cron_expression,
+	timezone, catch_up_policy, active, next_run_at, last_run_at, created_at, updated_at`
+
+type RecurrenceRepository struct {
+	db *sql.DB
+}
+
+func NewRecurrenceRepository(db *sql.DB) *RecurrenceRepository {
+	return &RecurrenceRepository{
+		db: db,
+	}
+}
+
+// CreateRecurrence creates a new recurrence that first fires at nextRunAt.
+func (r *RecurrenceRepository) CreateRecurrence(recurrenceInput *models.RecurrenceInput, publisherID int64, nextRunAt time.Time) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	INSERT INTO recurrences(
+		publisher_id,
+		title,
+		message,
+		priority,
+		topic,
+		recipients,
+		cron_expression,
+		timezone,
+		catch_up_policy,
+		active,
+		next_run_at,
+		created_at,
+		updated_at
+	) VALUES (($1), ($2), ($3), ($4), NULLIF($5, ''), ($6), ($7), ($8), ($9), ($10), ($11), ($12), ($13))`
+
+	_, err := r.db.Exec(query,
+		publisherID,
+		recurrenceInput.Title,
+		recurrenceInput.Message,
+		recurrenceInput.Priority,
+		recurrenceInput.Topic,
+		pq.Array(recurrenceInput.Recipients),
+		recurrenceInput.CronExpression,
+		recurrenceInput.Timezone,
+		recurrenceInput.CatchUpPolicy,
+		*recurrenceInput.Active,
+		nextRunAt.UTC().Format(time.RFC3339),
+		currentTime,
+		currentTime)
+	if err != nil {
+		log.Println("Error inserting recurrence:", err)
+	}
+	return err
+}
+
+// GetRecurrenceByID retrieves a recurrence by its ID from the database.
+func (r *RecurrenceRepository) GetRecurrenceByID(ID int64) (*models.Recurrence, error) {
+	query := `
+	SELECT ` + recurrenceColumns + `
+	FROM recurrences WHERE id = ($1)`
+
+	result := r.db.QueryRow(query, ID)
+
+	var recurrence models.Recurrence
+	err := scanRecurrence(result, &recurrence)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error retrieving recurrence:", err)
+			return nil, utils.ErrNotFound
+		}
+		log.Println("Error retrieving recurrence:", err)
+		return nil, err
+	}
+	return &recurrence, nil
+}
+
+// GetOwnRecurrences retrieves all recurrences of a publisher with pagination.
+func (r *RecurrenceRepository) GetOwnRecurrences(publisherID int64, page, pageSize int) ([]*models.Recurrence, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+	query := `
+	SELECT ` + recurrenceColumns + `
+	FROM recurrences WHERE publisher_id = $1
+	ORDER BY id
+	LIMIT $2 OFFSET $3`
+	results, err := r.db.Query(query, publisherID, pageSize, offset)
+	if err != nil {
+		log.Println("Error retrieving recurrences:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	return collectRecurrences(results)
+}
+
+// UpdateRecurrenceByID replaces the definition of a recurrence and reschedules it to fire at nextRunAt.
+func (r *RecurrenceRepository) UpdateRecurrenceByID(ID int64, recurrenceInput *models.RecurrenceInput, nextRunAt time.Time) error {
+	updatedAt := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	UPDATE recurrences SET
+		title = ($1),
+		message = ($2),
+		priority = ($3),
+		topic = NULLIF($4, ''),
+		recipients = ($5),
+		cron_expression = ($6),
+		timezone = ($7),
+		catch_up_policy = ($8),
+		active = ($9),
+		next_run_at = ($10),
+		updated_at = ($11)
+	WHERE id = ($12)`
+
+	result, err := r.db.Exec(query,
+		recurrenceInput.Title,
+		recurrenceInput.Message,
+		recurrenceInput.Priority,
+		recurrenceInput.Topic,
+		pq.Array(recurrenceInput.Recipients),
+		recurrenceInput.CronExpression,
+		recurrenceInput.Timezone,
+		recurrenceInput.CatchUpPolicy,
+		*recurrenceInput.Active,
+		nextRunAt.UTC().Format(time.RFC3339),
+		updatedAt,
+		ID)
+	if err != nil {
+		log.Println("Error updating recurrence:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+func (r *RecurrenceRepository) DeleteRecurrenceByID(ID int64) error {
+	query := `
+	DELETE FROM recurrences WHERE id = ($1)`
+
+	result, err := r.db.Exec(query, ID)
+	if err != nil {
+		log.Println("Error deleting recurrence:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+// ClaimDueRecurrences leases up to limit active recurrences that are due at now, so that no other
+// instance fires them until the lease expires or the run is completed.
+func (r *RecurrenceRepository) ClaimDueRecurrences(now time.Time, lease time.Duration, limit int) ([]*models.Recurrence, error) {
+	query := `
+	UPDATE recurrences
+	SET locked_until = $2
+	WHERE id IN (
+		SELECT id FROM recurrences
+		WHERE active AND next_run_at <= $1 AND (locked_until IS NULL OR locked_until < $1)
+		ORDER BY next_run_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED)
+	RETURNING ` + recurrenceColumns
+	results, err := r.db.Query(query, now.UTC().Format(time.RFC3339), now.Add(lease).UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		log.Println("Error claiming due recurrences:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	return collectRecurrences(results)
+}
+
+// CompleteRecurrenceRun records a run of a recurrence, schedules its next firing and releases its lease.
+// lastRunAt is nil when no notification was fired.
+func (r *RecurrenceRepository) CompleteRecurrenceRun(ID int64, lastRunAt *time.Time, nextRunAt time.Time) error {
+	var lastRun *string
+	if lastRunAt != nil {
+		formatted := lastRunAt.UTC().Format(time.RFC3339)
+		lastRun = &formatted
+	}
+
+	query := `
+	UPDATE recurrences
+	SET last_run_at = COALESCE($1, last_run_at),
+		next_run_at = $2,
+		locked_until = NULL
+	WHERE id = $3`
+
+	_, err := r.db.Exec(query, lastRun, nextRunAt.UTC().Format(time.RFC3339), ID)
+	if err != nil {
+		log.Println("Error completing recurrence run:", err)
+	}
+	return err
+}
+
+func collectRecurrences(results *sql.Rows) ([]*models.Recurrence, error) {
+	recurrences := []*models.Recurrence{}
+	for results.Next() {
+		var recurrence models.Recurrence
+		err := scanRecurrence(results, &recurrence)
+		if err != nil {
+			log.Println("Error scanning recurrence row:", err)
+			return nil, err
+		}
+		recurrences = append(recurrences, &recurrence)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over recurrence rows:", err)
+		return nil, err
+	}
+	return recurrences, nil
+}
+
+func scanRecurrence(row rowScanner, recurrence *models.Recurrence) error {
+	return row.Scan(
+		&recurrence.ID,
+		&recurrence.PublisherID,
+		&recurrence.Title,
+		&recurrence.Message,
+		&recurrence.Priority,
+		&recurrence.Topic,
+		pq.Array(&recurrence.Recipients),
+		&recurrence.CronExpression,
+		&recurrence.Timezone,
+		&recurrence.CatchUpPolicy,
+		&recurrence.Active,
+		&recurrence.NextRunAt,
+		&recurrence.LastRunAt,
+		&recurrence.CreatedAt,
+		&recurrence.UpdatedAt)
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/lib/pq"
+)
+
+// templateColumns lists the columns read by scanTemplate.
+const templateColumns = `id, publisher_id, name, title, message, translations, created_at, updated_at`
+
+type TemplateRepository struct {
+	db *sql.DB
+}
+
+func NewTemplateRepository(db *sql.DB) *TemplateRepository {
+	return &TemplateRepository{
+		db: db,
+	}
+}
+
+// CreateTemplate stores a template for a publisher and returns it.
+func (r *TemplateRepository) CreateTemplate(templateInput *models.TemplateInput, publisherID int64) (*models.Template, error) {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	INSERT INTO templates(
+		publisher_id,
+		name,
+		title,
+		message,
+		translations,
+		created_at,
+		updated_at
+	) VALUES (($1), ($2), ($3), ($4), ($5), ($6), ($7))
+	RETURNING ` + templateColumns
+
+	result := r.db.QueryRow(query, publisherID, templateInput.Name, templateInput.Title, templateInput.Message, templateInput.Translations, currentTime, currentTime)
+
+	var template models.Template
+	err := scanTemplate(result, &template)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23505" {
+				return nil, utils.ErrTemplateExists
+			}
+		}
+		log.Println("Error inserting template:", err)
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetTemplateByName retrieves a template of a publisher by its name.
+func (r *TemplateRepository) GetTemplateByName(publisherID int64, name string) (*models.Template, error) {
+	query := `
+	SELECT ` + templateColumns + `
+	FROM templates WHERE publisher_id = ($1) AND name = ($2)`
+
+	result := r.db.QueryRow(query, publisherID, name)
+
+	var template models.Template
+	err := scanTemplate(result, &template)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error retrieving template:", err)
+			return nil, utils.ErrNotFound
+		}
+		log.Println("Error retrieving template:", err)
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetOwnTemplates retrieves all templates of a publisher with pagination.
+func (r *TemplateRepository) GetOwnTemplates(publisherID int64, page, pageSize int) ([]*models.Template, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+	query := `
+	SELECT ` + templateColumns + `
+	FROM templates WHERE publisher_id = $1
+	ORDER BY name
+	LIMIT $2 OFFSET $3`
+	results, err := r.db.Query(query, publisherID, pageSize, offset)
+	if err != nil {
+		log.Println("Error retrieving templates:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	templates := []*models.Template{}
+	for results.Next() {
+		var template models.Template
+		err := scanTemplate(results, &template)
+		if err != nil {
+			log.Println("Error scanning template row:", err)
+			return nil, err
+		}
+		templates = append(templates, &template)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over template rows:", err)
+		return nil, err
+	}
+	return templates, nil
+}
+
+// UpdateTemplateByName replaces the name, title, message and translations of a publisher's template.
+func (r *TemplateRepository) UpdateTemplateByName(publisherID int64, name string, templateInput *models.TemplateInput) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	UPDATE templates SET
+		name = ($1),
+		title = ($2),
+		message = ($3),
+		translations = ($4),
+		updated_at = ($5)
+	WHERE publisher_id = ($6) AND name = ($7)`
+
+	result, err := r.db.Exec(query, templateInput.Name, templateInput.Title, templateInput.Message, templateInput.Translations, currentTime, publisherID, name)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23505" {
+				return utils.ErrTemplateExists
+			}
+		}
+		log.Println("Error updating template:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+// DeleteTemplateByName deletes a publisher's template.
+func (r *TemplateRepository) DeleteTemplateByName(publisherID int64, name string) error {
+	query := `DELETE FROM templates WHERE publisher_id = ($1) AND name = ($2)`
+
+	result, err := r.db.Exec(query, publisherID, name)
+	if err != nil {
+		log.Println("Error deleting template:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+func scanTemplate(row rowScanner, template *models.Template) error {
+	return row.Scan(
+		&template.ID,
+		&template.PublisherID,
+		&template.Name,
+		&template.Title,
+		&template.Message,
+		&template.Translations,
+		&template.CreatedAt,
+		&template.UpdatedAt)
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/lib/pq"
+)
+
+type TopicRepository struct {
+	db *sql.DB
+}
+
+func NewTopicRepository(db *sql.DB) *TopicRepository {
+	return &TopicRepository{
+		db: db,
+	}
+}
+
+// CreateTopic creates a new topic.
+func (r *TopicRepository) CreateTopic(topicInput *models.TopicInput, creatorID int64) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	INSERT INTO topics(
+		name,
+		description,
+		created_by,
+		created_at,
+		updated_at
+	) VALUES (($1), ($2), ($3), ($4), ($5))`
+
+	_, err := r.db.Exec(query, topicInput.Name, topicInput.Description, creatorID, currentTime, currentTime)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23505" {
+				return utils.ErrTopicExists
+			}
+		}
+		log.Println("Error inserting topic:", err)
+	}
+	return err
+}
+
+// GetTopicByName retrieves a topic by its name from the database.
+func (r *TopicRepository) GetTopicByName(name string) (*models.Topic, error) {
+	query := `
+	SELECT 
+	id, name, description, COALESCE(created_by, 0), created_at, updated_at
+	FROM topics
+	WHERE name = ($1)`
+
+	result := r.db.QueryRow(query, name)
+
+	var topic models.Topic
+	err := result.Scan(&topic.ID, &topic.Name, &topic.Description, &topic.CreatedBy, &topic.CreatedAt, &topic.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error retrieving topic:", err)
+			return nil, utils.ErrNotFound
+		}
+		log.Println("Error retrieving topic:", err)
+		return nil, err
+	}
+	return &topic, nil
+}
+
+// GetAllTopics retrieves all topics from the database with pagination.
+func (r *TopicRepository) GetAllTopics(page, pageSize int) ([]*models.Topic, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+	query := `
+	SELECT 
+	id, name, description, COALESCE(created_by, 0), created_at, updated_at
+	FROM topics
+	ORDER BY name
+	LIMIT $1
+	OFFSET $2`
+	results, err := r.db.Query(query, pageSize, offset)
+	if err != nil {
+		log.Println("Error retrieving topics:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	topics := []*models.Topic{}
+	for results.Next() {
+		var topic models.Topic
+		err := results.Scan(&topic.ID, &topic.Name, &topic.Description, &topic.CreatedBy, &topic.CreatedAt, &topic.UpdatedAt)
+		if err != nil {
+			log.Println("Error scanning topic row:", err)
+			return nil, err
+		}
+		topics = append(topics, &topic)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over topic rows:", err)
+		return nil, err
+	}
+	return topics, nil
+}
+
+// CreateSubscription subscribes a user to a topic. Subscribing twice is not an error.
+func (r *TopicRepository) CreateSubscription(topicName string, userID int64) error {
+	topic, err := r.GetTopicByName(topicName)
+	if err != nil {
+		return err
+	}
+
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	INSERT INTO subscriptions(
+		topic_id,
+		user_id,
+		created_at
+	) VALUES (($1), ($2), ($3))
+	ON CONFLICT (topic_id, user_id) DO NOTHING`
+
+	_, err = r.db.Exec(query, topic.ID, userID, currentTime)
+	if err != nil {
+		log.Println("Error inserting subscription:", err)
+	}
+	return err
+}
+
+// DeleteSubscription unsubscribes a user from a topic.
+func (r *TopicRepository) DeleteSubscription(topicName string, userID int64) error {
+	query := `
+	DELETE FROM subscriptions
+	WHERE user_id = ($1) AND topic_id = (SELECT id FROM topics WHERE name = ($2))`
+
+	result, err := r.db.Exec(query, userID, topicName)
+	if err != nil {
+		log.Println("Error deleting subscription:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+// GetSubscriptions retrieves every topic subscription of a user.
+func (r *TopicRepository) GetSubscriptions(userID int64) ([]*models.Subscription, error) {
+	query := `
+	SELECT s.id, t.name, s.user_id, s.created_at
+	FROM subscriptions s
+	INNER JOIN topics t ON t.id = s.topic_id
+	WHERE s.user_id = $1
+	ORDER BY t.name`
+	results, err := r.db.Query(query, userID)
+	if err != nil {
+		log.Println("Error retrieving subscriptions:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	subscriptions := []*models.Subscription{}
+	for results.Next() {
+		var subscription models.Subscription
+		err := results.Scan(&subscription.ID, &subscription.Topic, &subscription.UserID, &subscription.CreatedAt)
+		if err != nil {
+			log.Println("Error scanning subscription row:", err)
+			return nil, err
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over subscription rows:", err)
+		return nil, err
+	}
+	return subscriptions, nil
+}
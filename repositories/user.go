@@ -3,8 +3,10 @@ package repositories
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/akinolaemmanuel49/notify-api/models"
@@ -12,6 +14,26 @@ import (
 	"github.com/lib/pq"
 )
 
+// userProfileColumns lists the columns read by scanUserProfile.
+const userProfileColumns = `id, first_name, last_name, email, email_notifications, digest_frequency, timezone, locale,
+	quiet_hours_start, quiet_hours_end, do_not_disturb, high_priority_breakthrough, email_verified_at, created_at, updated_at`
+
+// updatableUserColumns lists the columns UpdateUserByID lets clients set. Every other column is
+// either read-only or maintained by the API.
+var updatableUserColumns = map[string]bool{
+	"first_name":                 true,
+	"last_name":                  true,
+	"email":                      true,
+	"timezone":                   true,
+	"locale":                     true,
+	"quiet_hours_start":          true,
+	"quiet_hours_end":            true,
+	"do_not_disturb":             true,
+	"high_priority_breakthrough": true,
+	"email_notifications":        true,
+	"digest_frequency":           true,
+}
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -23,7 +45,7 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 }
 
 // CreateUser creates a new instance of UserRepository
-func (r *UserRepository) CreateUser(userInput *models.UserInput, password string) error {
+func (r *UserRepository) CreateUser(userInput *models.UserInput, password string) (*models.UserProfile, error) {
 	currentTime := time.Now().UTC().Format(time.RFC3339)
 	hashedPassword, err := utils.GenerateHashPassword(password)
 	if err != nil {
@@ -35,6 +57,7 @@ func (r *UserRepository) CreateUser(userInput *models.UserInput, password string
 		LastName:     userInput.LastName,
 		Email:        userInput.Email,
 		PasswordHash: hashedPassword,
+		Timezone:     userInput.Timezone,
 		CreatedAt:    currentTime,
 		UpdatedAt:    currentTime,
 	}
@@ -44,33 +67,38 @@ func (r *UserRepository) CreateUser(userInput *models.UserInput, password string
 		last_name,
 		email,
 		password_hash,
+		timezone,
 		created_at,
 		updated_at
-	) VALUES (($1), ($2), ($3), ($4), ($5), ($6))`
+	) VALUES (($1), ($2), ($3), ($4), ($5), ($6), ($7))
+	RETURNING ` + userProfileColumns
 
-	_, err = r.db.Exec(query, user.FirstName, user.LastName, user.Email, user.PasswordHash, user.CreatedAt, user.UpdatedAt)
+	result := r.db.QueryRow(query, user.FirstName, user.LastName, user.Email, user.PasswordHash, user.Timezone, user.CreatedAt, user.UpdatedAt)
+
+	var userProfile models.UserProfile
+	err = scanUserProfile(result, &userProfile)
 	if err != nil {
 		if pgErr, ok := err.(*pq.Error); ok {
 			if pgErr.Code == "23505" {
-				return utils.ErrDuplicateKey
+				return nil, utils.ErrDuplicateKey
 			}
 		}
 		log.Println("Error inserting user:", err)
+		return nil, err
 	}
-	return err
+	return &userProfile, nil
 }
 
 func (r *UserRepository) GetUserByID(id int64) (*models.UserProfile, error) {
 	query := `
-	SELECT 
-	id, first_name, last_name, email, created_at, updated_at
+	SELECT ` + userProfileColumns + `
 	FROM users
 	WHERE id = ($1)`
 
 	result := r.db.QueryRow(query, id)
 
 	var userProfile models.UserProfile
-	err := result.Scan(&userProfile.ID, &userProfile.FirstName, &userProfile.LastName, &userProfile.Email, &userProfile.CreatedAt, &userProfile.UpdatedAt)
+	err := scanUserProfile(result, &userProfile)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			log.Println("Error retrieving user:", err)
@@ -82,14 +110,33 @@ func (r *UserRepository) GetUserByID(id int64) (*models.UserProfile, error) {
 	return &userProfile, nil
 }
 
+// GetUserByEmail retrieves the user an email address belongs to.
+func (r *UserRepository) GetUserByEmail(email string) (*models.UserProfile, error) {
+	query := `
+	SELECT ` + userProfileColumns + `
+	FROM users WHERE email = ($1)`
+
+	result := r.db.QueryRow(query, email)
+
+	var userProfile models.UserProfile
+	err := scanUserProfile(result, &userProfile)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, utils.ErrNotFound
+		}
+		log.Println("Error retrieving user:", err)
+		return nil, err
+	}
+	return &userProfile, nil
+}
+
 func (r *UserRepository) GetAllUsers(page, pageSize int) ([]*models.UserProfile, error) {
 	if page < 1 {
 		page = 1
 	}
 	offset := (page - 1) * pageSize
 	query := `
-	SELECT 
-	id, first_name, last_name, email, created_at, updated_at
+	SELECT ` + userProfileColumns + `
 	FROM users
 	LIMIT $1
 	OFFSET $2`
@@ -103,7 +150,7 @@ func (r *UserRepository) GetAllUsers(page, pageSize int) ([]*models.UserProfile,
 	userProfiles := []*models.UserProfile{}
 	for results.Next() {
 		var userProfile models.UserProfile
-		err := results.Scan(&userProfile.ID, &userProfile.FirstName, &userProfile.LastName, &userProfile.Email, &userProfile.CreatedAt, &userProfile.UpdatedAt)
+		err := scanUserProfile(results, &userProfile)
 		if err != nil {
 			log.Println("Error scanning user row:", err)
 			return nil, err
@@ -117,35 +164,39 @@ func (r *UserRepository) GetAllUsers(page, pageSize int) ([]*models.UserProfile,
 	return userProfiles, nil
 }
 
+// UpdateUserByID sets the given columns of a user, which must all be listed in
+// updatableUserColumns; any other key is rejected with ErrUnknownUserField. Changing the email
+// address marks it as unverified.
 func (r *UserRepository) UpdateUserByID(id int64, fields map[string]interface{}) error {
+	for key := range fields {
+		if !updatableUserColumns[key] {
+			return fmt.Errorf("%w: %s", utils.ErrUnknownUserField, key)
+		}
+	}
+
 	_, err := r.GetUserByID(id)
 	if errors.Is(err, utils.ErrNotFound) {
 		log.Println("Error updating user:", err)
 		return utils.ErrNotFound
 	}
 
-	query := "UPDATE users SET "
-
+	var assignments []string
 	var params []interface{}
-	i := 1
 	for key, value := range fields {
-		if key == "password" || key == "created_at" || key == "updated_at" {
-			continue
-		}
-
-		if i > 1 {
-			query += ", "
-		}
-		query += key + " = $" + strconv.Itoa(i)
 		params = append(params, value)
-		i++
+		assignments = append(assignments, key+" = $"+strconv.Itoa(len(params)))
+		if key == "email" {
+			// The right-hand side refers to the address before the update
+			assignments = append(assignments, "email_verified_at = CASE WHEN lower(email) = lower($"+strconv.Itoa(len(params))+
+				") THEN email_verified_at ELSE NULL END")
+		}
 	}
 
-	query += ", updated_at = $" + strconv.Itoa(i)
-	query += " WHERE id = $" + strconv.Itoa(i+1)
-
 	updatedAt := time.Now().UTC().Format(time.RFC3339)
 	params = append(params, updatedAt, id)
+	assignments = append(assignments, "updated_at = $"+strconv.Itoa(len(params)-1))
+
+	query := "UPDATE users SET " + strings.Join(assignments, ", ") + " WHERE id = $" + strconv.Itoa(len(params))
 
 	_, err = r.db.Exec(query, params...)
 	if err != nil {
@@ -154,6 +205,21 @@ func (r *UserRepository) UpdateUserByID(id int64, fields map[string]interface{})
 	return err
 }
 
+// ScheduleDigest sets when the next digest of a user is due, nil when they receive none.
+func (r *UserRepository) ScheduleDigest(id int64, nextAt *time.Time) error {
+	query := `UPDATE users SET digest_next_at = ($1) WHERE id = ($2)`
+
+	var nextDigestAt interface{}
+	if nextAt != nil {
+		nextDigestAt = nextAt.UTC().Format(time.RFC3339)
+	}
+	_, err := r.db.Exec(query, nextDigestAt, id)
+	if err != nil {
+		log.Println("Error scheduling digest:", err)
+	}
+	return err
+}
+
 func (r *UserRepository) DeleteUserByID(id int64) error {
 	_, err := r.GetUserByID(id)
 	if errors.Is(err, utils.ErrNotFound) {
@@ -170,3 +236,92 @@ func (r *UserRepository) DeleteUserByID(id int64) error {
 	}
 	return err
 }
+
+// GetLocale retrieves the locale notifications are shown to a user in. An empty locale means the default.
+func (r *UserRepository) GetLocale(id int64) (string, error) {
+	query := `SELECT locale FROM users WHERE id = ($1)`
+
+	var locale string
+	err := r.db.QueryRow(query, id).Scan(&locale)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", utils.ErrNotFound
+		}
+		log.Println("Error retrieving user locale:", err)
+		return "", err
+	}
+	return locale, nil
+}
+
+// GetPasswordHash retrieves the bcrypt hash of a user's password.
+func (r *UserRepository) GetPasswordHash(id int64) (string, error) {
+	query := `SELECT password_hash FROM users WHERE id = ($1)`
+
+	var passwordHash string
+	err := r.db.QueryRow(query, id).Scan(&passwordHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", utils.ErrNotFound
+		}
+		log.Println("Error retrieving password hash:", err)
+		return "", err
+	}
+	return passwordHash, nil
+}
+
+// UpdatePassword replaces the password hash of a user and revokes the tokens and sessions issued to them before now.
+func (r *UserRepository) UpdatePassword(id int64, passwordHash string, now time.Time) error {
+	currentTime := now.UTC().Format(time.RFC3339)
+
+	query := `
+	WITH revoked_families AS (
+		UPDATE token_families SET revoked_at = ($2) WHERE user_id = ($3) AND revoked_at IS NULL)
+	UPDATE users
+	SET password_hash = ($1), tokens_valid_after = ($2), updated_at = ($2)
+	WHERE id = ($3)`
+
+	result, err := r.db.Exec(query, passwordHash, currentTime, id)
+	if err != nil {
+		log.Println("Error updating password:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+// IsEmailVerified reports whether a user has confirmed their email address.
+func (r *UserRepository) IsEmailVerified(id int64) (bool, error) {
+	query := `SELECT email_verified_at IS NOT NULL FROM users WHERE id = ($1)`
+
+	var verified bool
+	err := r.db.QueryRow(query, id).Scan(&verified)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, utils.ErrNotFound
+		}
+		log.Println("Error retrieving email verification:", err)
+		return false, err
+	}
+	return verified, nil
+}
+
+// IsAdmin reports whether a user has administrator privileges.
+func (r *UserRepository) IsAdmin(id int64) (bool, error) {
+	query := `SELECT is_admin FROM users WHERE id = ($1)`
+
+	var isAdmin bool
+	err := r.db.QueryRow(query, id).Scan(&isAdmin)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, utils.ErrNotFound
+		}
+		log.Println("Error retrieving user role:", err)
+		return false, err
+	}
+	return isAdmin, nil
+}
+
+func scanUserProfile(row rowScanner, userProfile *models.UserProfile) error {
+	return row.Scan(&userProfile.ID, &userProfile.FirstName, &userProfile.LastName, &userProfile.Email, &userProfile.EmailNotifications,
+		&userProfile.DigestFrequency, &userProfile.Timezone, &userProfile.Locale, &userProfile.QuietHoursStart, &userProfile.QuietHoursEnd,
+		&userProfile.DoNotDisturb, &userProfile.HighPriorityBreakthrough, &userProfile.EmailVerifiedAt, &userProfile.CreatedAt, &userProfile.UpdatedAt)
+}
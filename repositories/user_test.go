@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+func TestUpdateUserByIDRejectsUnknownFields(t *testing.T) {
+	// Fields are checked before the database is touched
+	r := NewUserRepository(nil)
+
+	for _, key := range []string{
+		"is_admin",
+		"IS_ADMIN",
+		"First_Name",
+		"id",
+		"created_at",
+		"password",
+		"password_hash",
+		"tokens_valid_after",
+		"email_verified_at",
+		"EMAIL_VERIFIED_AT",
+		"digest_next_at",
+		"first_name = 'x', is_admin",
+	} {
+		err := r.UpdateUserByID(1, map[string]interface{}{"first_name": "Jane", key: true})
+		if !errors.Is(err, utils.ErrUnknownUserField) {
+			t.Errorf("UpdateUserByID with %q: got error %v, want ErrUnknownUserField", key, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+type VerificationRepository struct {
+	db *sql.DB
+}
+
+func NewVerificationRepository(db *sql.DB) *VerificationRepository {
+	return &VerificationRepository{
+		db: db,
+	}
+}
+
+// CreateVerificationToken stores the hash of a new verification token for a user. Tokens sent
+// earlier stop working, so only the most recent email can be used.
+func (r *VerificationRepository) CreateVerificationToken(userID int64, tokenHash string, expiresAt time.Time) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM email_verification_tokens WHERE user_id = ($1)`
+
+	_, err = tx.Exec(query, userID)
+	if err != nil {
+		log.Println("Error deleting verification tokens:", err)
+		return err
+	}
+
+	query = `
+	INSERT INTO email_verification_tokens(
+		user_id,
+		token_hash,
+		expires_at,
+		created_at
+	) VALUES (($1), ($2), ($3), ($4))`
+
+	_, err = tx.Exec(query, userID, tokenHash, expiresAt.UTC().Format(time.RFC3339), currentTime)
+	if err != nil {
+		log.Println("Error inserting verification token:", err)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Println("Error committing verification token:", err)
+	}
+	return err
+}
+
+// VerifyEmail uses up a verification token and marks the email address of its user as verified.
+// It returns the ID of the user.
+func (r *VerificationRepository) VerifyEmail(tokenHash string, now time.Time) (int64, error) {
+	currentTime := now.UTC().Format(time.RFC3339)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Deleting the token makes it single-use, even when two requests race to verify
+	query := `
+	DELETE FROM email_verification_tokens
+	WHERE token_hash = ($1) AND expires_at > ($2)
+	RETURNING user_id`
+
+	var userID int64
+	err = tx.QueryRow(query, tokenHash, currentTime).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, utils.ErrInvalidVerificationToken
+		}
+		log.Println("Error using verification token:", err)
+		return 0, err
+	}
+
+	query = `
+	UPDATE users
+	SET email_verified_at = COALESCE(email_verified_at, ($1))
+	WHERE id = ($2)`
+
+	_, err = tx.Exec(query, currentTime, userID)
+	if err != nil {
+		log.Println("Error verifying email address:", err)
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Println("Error committing email verification:", err)
+		return 0, err
+	}
+	return userID, nil
+}
+
+// MarkEmailVerified marks the email address of a user as verified without a token.
+func (r *VerificationRepository) MarkEmailVerified(userID int64, now time.Time) error {
+	currentTime := now.UTC().Format(time.RFC3339)
+
+	query := `
+	UPDATE users
+	SET email_verified_at = COALESCE(email_verified_at, ($1))
+	WHERE id = ($2)`
+
+	result, err := r.db.Exec(query, currentTime, userID)
+	if err != nil {
+		log.Println("Error verifying email address:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
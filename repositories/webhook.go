@@ -0,0 +1,264 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/lib/pq"
+)
+
+// webhookColumns lists the columns read by scanWebhook.
+const webhookColumns = `id, user_id, url, format, COALESCE(topic, ''), secret, active, created_at, updated_at`
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+// CreateWebhook registers a webhook endpoint for a user and returns it.
+func (r *WebhookRepository) CreateWebhook(webhookInput *models.WebhookInput, userID int64) (*models.Webhook, error) {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	INSERT INTO webhooks(
+		user_id,
+		url,
+		format,
+		topic,
+		secret,
+		active,
+		created_at,
+		updated_at
+	) VALUES (($1), ($2), ($3), NULLIF($4, ''), ($5), ($6), ($7), ($8))
+	RETURNING ` + webhookColumns
+
+	result := r.db.QueryRow(query, userID, webhookInput.URL, webhookInput.Format, webhookInput.Topic, webhookInput.Secret, *webhookInput.Active, currentTime, currentTime)
+
+	var webhook models.Webhook
+	err := scanWebhook(result, &webhook)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23503" {
+				return nil, utils.ErrTopicNotFound
+			}
+		}
+		log.Println("Error inserting webhook:", err)
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetWebhookByID retrieves a webhook by its ID from the database.
+func (r *WebhookRepository) GetWebhookByID(ID int64) (*models.Webhook, error) {
+	query := `
+	SELECT ` + webhookColumns + `
+	FROM webhooks WHERE id = ($1)`
+
+	result := r.db.QueryRow(query, ID)
+
+	var webhook models.Webhook
+	err := scanWebhook(result, &webhook)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error retrieving webhook:", err)
+			return nil, utils.ErrNotFound
+		}
+		log.Println("Error retrieving webhook:", err)
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetOwnWebhooks retrieves all webhooks of a user with pagination.
+func (r *WebhookRepository) GetOwnWebhooks(userID int64, page, pageSize int) ([]*models.Webhook, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+	query := `
+	SELECT ` + webhookColumns + `
+	FROM webhooks WHERE user_id = $1
+	ORDER BY id
+	LIMIT $2 OFFSET $3`
+	results, err := r.db.Query(query, userID, pageSize, offset)
+	if err != nil {
+		log.Println("Error retrieving webhooks:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	return collectWebhooks(results)
+}
+
+// GetActiveWebhooks retrieves the active webhooks of the given format owned by any of the given users.
+// Webhooks scoped to a topic are only returned for that topic.
+func (r *WebhookRepository) GetActiveWebhooks(userIDs []int64, format, topic string) ([]*models.Webhook, error) {
+	query := `
+	SELECT ` + webhookColumns + `
+	FROM webhooks
+	WHERE active AND format = $2 AND user_id = ANY($1) AND (topic IS NULL OR topic = $3)
+	ORDER BY id`
+	results, err := r.db.Query(query, pq.Array(userIDs), format, topic)
+	if err != nil {
+		log.Println("Error retrieving active webhooks:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	return collectWebhooks(results)
+}
+
+// UpdateWebhookByID updates the url, format, topic and active flag of a webhook, and its secret when one is given.
+func (r *WebhookRepository) UpdateWebhookByID(ID int64, webhookInput *models.WebhookInput) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	UPDATE webhooks SET
+		url = ($1),
+		format = ($2),
+		topic = NULLIF($3, ''),
+		secret = COALESCE(NULLIF($4, ''), secret),
+		active = ($5),
+		updated_at = ($6)
+	WHERE id = ($7)`
+
+	result, err := r.db.Exec(query, webhookInput.URL, webhookInput.Format, webhookInput.Topic, webhookInput.Secret, *webhookInput.Active, currentTime, ID)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok {
+			if pgErr.Code == "23503" {
+				return utils.ErrTopicNotFound
+			}
+		}
+		log.Println("Error updating webhook:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+// DeleteWebhookByID deletes a webhook and its delivery history.
+func (r *WebhookRepository) DeleteWebhookByID(ID int64) error {
+	query := `DELETE FROM webhooks WHERE id = ($1)`
+
+	result, err := r.db.Exec(query, ID)
+	if err != nil {
+		log.Println("Error deleting webhook:", err)
+		return err
+	}
+	return requireAffectedRows(result)
+}
+
+// CreateWebhookDelivery records a delivery attempt.
+func (r *WebhookRepository) CreateWebhookDelivery(delivery *models.WebhookDelivery) error {
+	currentTime := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+	INSERT INTO webhook_deliveries(
+		webhook_id,
+		notification_id,
+		attempt,
+		status_code,
+		success,
+		error,
+		duration_ms,
+		created_at
+	) VALUES (($1), ($2), ($3), ($4), ($5), ($6), ($7), ($8))`
+
+	_, err := r.db.Exec(query,
+		delivery.WebhookID,
+		delivery.NotificationID,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Success,
+		delivery.Error,
+		delivery.DurationMS,
+		currentTime)
+	if err != nil {
+		log.Println("Error inserting webhook delivery:", err)
+	}
+	return err
+}
+
+// GetWebhookDeliveries retrieves the delivery attempts of a webhook, newest first, with pagination.
+func (r *WebhookRepository) GetWebhookDeliveries(webhookID int64, page, pageSize int) ([]*models.WebhookDelivery, error) {
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+	query := `
+	SELECT id, webhook_id, notification_id, attempt, status_code, success, error, duration_ms, created_at
+	FROM webhook_deliveries
+	WHERE webhook_id = $1
+	ORDER BY id DESC
+	LIMIT $2 OFFSET $3`
+	results, err := r.db.Query(query, webhookID, pageSize, offset)
+	if err != nil {
+		log.Println("Error retrieving webhook deliveries:", err)
+		return nil, err
+	}
+	defer results.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for results.Next() {
+		var delivery models.WebhookDelivery
+		err := results.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.NotificationID,
+			&delivery.Attempt,
+			&delivery.StatusCode,
+			&delivery.Success,
+			&delivery.Error,
+			&delivery.DurationMS,
+			&delivery.CreatedAt)
+		if err != nil {
+			log.Println("Error scanning webhook delivery row:", err)
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over webhook delivery rows:", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func collectWebhooks(results *sql.Rows) ([]*models.Webhook, error) {
+	webhooks := []*models.Webhook{}
+	for results.Next() {
+		var webhook models.Webhook
+		err := scanWebhook(results, &webhook)
+		if err != nil {
+			log.Println("Error scanning webhook row:", err)
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+	if err := results.Err(); err != nil {
+		log.Println("Error iterating over webhook rows:", err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func scanWebhook(row rowScanner, webhook *models.Webhook) error {
+	return row.Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Format,
+		&webhook.Topic,
+		&webhook.Secret,
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt)
+}
@@ -1,17 +1,31 @@
 package services
 
 import (
+	"errors"
+	"time"
+
 	"github.com/akinolaemmanuel49/notify-api/models"
 	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// refreshTokenBytes is the number of random bytes in a refresh token.
+	refreshTokenBytes = 32
+	// tokenFamilyIDBytes is the number of random bytes in the ID of a token family.
+	tokenFamilyIDBytes = 16
 )
 
 type AuthService struct {
-	authRepository *repositories.AuthRepository
+	authRepository  *repositories.AuthRepository
+	refreshTokenTTL time.Duration
 }
 
-func NewAuthService(authRepository *repositories.AuthRepository) *AuthService {
+func NewAuthService(authRepository *repositories.AuthRepository, refreshTokenTTL time.Duration) *AuthService {
 	return &AuthService{
-		authRepository: authRepository,
+		authRepository:  authRepository,
+		refreshTokenTTL: refreshTokenTTL,
 	}
 }
 
@@ -22,3 +36,104 @@ func (s *AuthService) AuthenticateUser(credentials *models.AuthCredentials) (boo
 	}
 	return ok, ID, nil
 }
+
+// IssueTokens starts a session for a user and returns its access and refresh tokens.
+func (s *AuthService) IssueTokens(userID int64) (*models.TokenPair, error) {
+	familyID, err := utils.GenerateSecret(tokenFamilyIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := utils.GenerateSecret(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	err = s.authRepository.CreateTokenFamily(userID, familyID, utils.HashToken(refreshToken), time.Now().Add(s.refreshTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+	return newTokenPair(userID, familyID, refreshToken)
+}
+
+// RefreshTokens exchanges a refresh token for a new access token and a new refresh token. Each
+// refresh token can be used once; using one again revokes every token of its session.
+func (s *AuthService) RefreshTokens(refreshToken string) (*models.TokenPair, error) {
+	if refreshToken == "" {
+		return nil, utils.ErrInvalidRefreshToken
+	}
+	newRefreshToken, err := utils.GenerateSecret(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	userID, familyID, err := s.authRepository.RotateRefreshToken(utils.HashToken(refreshToken), utils.HashToken(newRefreshToken), now.Add(s.refreshTokenTTL), now)
+	if err != nil {
+		return nil, err
+	}
+	return newTokenPair(userID, familyID, newRefreshToken)
+}
+
+// Logout revokes the access token a user presented and ends the session it belongs to.
+func (s *AuthService) Logout(claims jwt.MapClaims) error {
+	tokenID, _ := claims["jti"].(string)
+	expiresAt, err := claims.GetExpirationTime()
+	if err == nil && expiresAt != nil && tokenID != "" {
+		if err := s.authRepository.RevokeToken(tokenID, expiresAt.Time); err != nil {
+			return err
+		}
+	}
+	if familyID, _ := claims["sid"].(string); familyID != "" {
+		return s.authRepository.RevokeTokenFamily(familyID, time.Now())
+	}
+	return nil
+}
+
+// IsTokenRevoked reports whether a validly signed token may no longer be used, because it was
+// logged out, its session was revoked, or its user has been deleted or changed their password after
+// it was issued. Tokens without an ID cannot be revoked and are refused.
+func (s *AuthService) IsTokenRevoked(claims jwt.MapClaims) (bool, error) {
+	ID, ok := claims["id"].(float64)
+	if !ok {
+		return true, nil
+	}
+	tokenID, _ := claims["jti"].(string)
+	if tokenID == "" {
+		return true, nil
+	}
+	familyID, _ := claims["sid"].(string)
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return true, nil
+	}
+	validAfter, revoked, err := s.authRepository.GetTokenStatus(int64(ID), tokenID, familyID)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	if revoked {
+		return true, nil
+	}
+	// Tokens carry their issue time in whole seconds, so one issued in the second of a password
+	// change is still accepted
+	return validAfter != nil && issuedAt.Unix() < validAfter.Unix(), nil
+}
+
+// PurgeExpiredTokens removes revocations and sessions that have expired and returns how many were removed.
+func (s *AuthService) PurgeExpiredTokens() (int64, error) {
+	return s.authRepository.DeleteExpiredTokens(time.Now())
+}
+
+// newTokenPair issues an access token for a session and pairs it with the session's refresh token.
+func newTokenPair(userID int64, familyID, refreshToken string) (*models.TokenPair, error) {
+	token, expiresAt, err := utils.GenerateJWT(userID, familyID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenPair{
+		Token:        token,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(expiresAt).Round(time.Second).Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
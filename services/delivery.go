@@ -0,0 +1,361 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/channels"
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// deliveryBatchSize is the number of jobs claimed and run concurrently per query.
+const deliveryBatchSize = 20
+
+// heldBatchSize is the number of held deliveries released per query.
+const heldBatchSize = 200
+
+// deliveryLease is how long a claimed job is hidden from other workers. It must outlast a delivery attempt.
+const deliveryLease = 2 * time.Minute
+
+// Retry delays double with every attempt, from deliveryBackoffBase up to deliveryBackoffMax.
+const (
+	deliveryBackoffBase = 30 * time.Second
+	deliveryBackoffMax  = time.Hour
+)
+
+type DeliveryService struct {
+	deliveryRepository     *repositories.DeliveryRepository
+	notificationRepository *repositories.NotificationRepository
+	userRepository         *repositories.UserRepository
+	preferenceRepository   *repositories.PreferenceRepository
+	registry               *channels.Registry
+	maxAttempts            int
+}
+
+func NewDeliveryService(deliveryRepository *repositories.DeliveryRepository, notificationRepository *repositories.NotificationRepository, userRepository *repositories.UserRepository, preferenceRepository *repositories.PreferenceRepository, registry *channels.Registry, maxAttempts int) *DeliveryService {
+	return &DeliveryService{
+		deliveryRepository:     deliveryRepository,
+		notificationRepository: notificationRepository,
+		userRepository:         userRepository,
+		preferenceRepository:   preferenceRepository,
+		registry:               registry,
+		maxAttempts:            maxAttempts,
+	}
+}
+
+// GetChannels describes the delivery channels enabled on this server.
+func (s *DeliveryService) GetChannels() []*models.ChannelInfo {
+	infos := []*models.ChannelInfo{}
+	for _, channel := range s.registry.Channels() {
+		infos = append(infos, &models.ChannelInfo{Name: channel.Name(), Capabilities: channel.Capabilities()})
+	}
+	return infos
+}
+
+// Enqueue dispatches a notification to the enabled channels each recipient wants it on. Recipients
+// in their quiet hours are skipped and the notification is held for them until the quiet hours end.
+// Deliveries over durable channels are queued, the others are sent right away. A failing channel
+// does not hold up the others.
+func (s *DeliveryService) Enqueue(notification *models.Notification, recipientIDs []int64) error {
+	if len(recipientIDs) == 0 {
+		return nil
+	}
+	preferences, err := s.preferenceRepository.GetRecipientPreferences(recipientIDs)
+	if err != nil {
+		return err
+	}
+	var errs []error
+
+	now := time.Now()
+	held := map[int64]*time.Time{}
+	undisturbed := []int64{}
+	for _, recipientID := range recipientIDs {
+		if recipientPreferences, ok := preferences[recipientID]; ok {
+			if hold, releaseAt := recipientPreferences.QuietHours.HoldUntil(notification.Priority, now); hold {
+				held[recipientID] = releaseAt
+				continue
+			}
+		}
+		undisturbed = append(undisturbed, recipientID)
+	}
+	if len(held) > 0 {
+		if err := s.deliveryRepository.HoldDeliveries(notification.ID, held); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	recipientIDs = undisturbed
+	if len(recipientIDs) == 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, channel := range s.registry.Channels() {
+		candidates, err := channel.Targets(notification, recipientIDs)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Name(), err))
+			continue
+		}
+		targets := []models.DeliveryTarget{}
+		for _, target := range candidates {
+			if channelWanted(preferences[target.RecipientID], notification, channel.Name()) {
+				targets = append(targets, target)
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		if channel.Capabilities().Durable {
+			if err := s.deliveryRepository.EnqueueJobs(channel.Name(), notification.ID, targets, s.maxAttempts); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", channel.Name(), err))
+			}
+			continue
+		}
+		for _, target := range targets {
+			delivery := &models.Delivery{DeliveryTarget: target, NotificationID: notification.ID, Attempt: 1}
+			if err := channel.Send(delivery, notification); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", channel.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ProcessDueJobs runs every job that is due. It returns the number of jobs attempted.
+func (s *DeliveryService) ProcessDueJobs() (int, error) {
+	processed := 0
+	for {
+		jobs, err := s.deliveryRepository.ClaimDueJobs(time.Now(), deliveryLease, deliveryBatchSize)
+		if err != nil {
+			return processed, err
+		}
+		var wg sync.WaitGroup
+		for _, job := range jobs {
+			wg.Add(1)
+			go func(job *models.DeliveryJob) {
+				defer wg.Done()
+				s.run(job)
+			}(job)
+		}
+		wg.Wait()
+		processed += len(jobs)
+		if len(jobs) < deliveryBatchSize {
+			return processed, nil
+		}
+	}
+}
+
+// ReleaseHeldDeliveries dispatches the notifications held for recipients whose quiet hours have ended,
+// in a single batch per notification. Recipients are checked again, so a delivery released while
+// another quiet period is in effect is held again. It returns the number of deliveries released.
+func (s *DeliveryService) ReleaseHeldDeliveries() (int, error) {
+	released := 0
+	for {
+		heldDeliveries, err := s.deliveryRepository.ReleaseHeldDeliveries(time.Now(), heldBatchSize)
+		if err != nil {
+			return released, err
+		}
+		notificationIDs := []int64{}
+		recipientIDs := map[int64][]int64{}
+		for _, heldDelivery := range heldDeliveries {
+			if _, ok := recipientIDs[heldDelivery.NotificationID]; !ok {
+				notificationIDs = append(notificationIDs, heldDelivery.NotificationID)
+			}
+			recipientIDs[heldDelivery.NotificationID] = append(recipientIDs[heldDelivery.NotificationID], heldDelivery.RecipientID)
+		}
+		for _, notificationID := range notificationIDs {
+			s.release(notificationID, recipientIDs[notificationID])
+		}
+		released += len(heldDeliveries)
+		if len(heldDeliveries) < heldBatchSize {
+			return released, nil
+		}
+	}
+}
+
+// release dispatches a held notification to recipients. The held deliveries are already removed,
+// so failures are logged rather than returned.
+func (s *DeliveryService) release(notificationID int64, recipientIDs []int64) {
+	notification, err := s.notificationRepository.GetNotificationByID(notificationID)
+	if err != nil {
+		if !errors.Is(err, utils.ErrNotFound) {
+			log.Println("Error releasing held deliveries:", err)
+		}
+		return
+	}
+	if isExpired(notification, time.Now()) {
+		return
+	}
+	if err := s.Enqueue(notification, recipientIDs); err != nil {
+		log.Println("Error releasing held deliveries:", err)
+	}
+}
+
+// isExpired reports whether a notification is no longer worth delivering at now.
+func isExpired(notification *models.Notification, now time.Time) bool {
+	if notification.ExpiresAt == nil {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, *notification.ExpiresAt)
+	return err == nil && !expiresAt.After(now)
+}
+
+// channelWanted applies a recipient's preferences to a channel. Without a matching rule every channel
+// is used, except email which is kept for High priority notifications unless the recipient opted in.
+// Recipients who receive digests get Low and Mid priority notifications by email only in their digest.
+func channelWanted(preferences *models.RecipientPreferences, notification *models.Notification, channel string) bool {
+	// The recipient no longer exists
+	if preferences == nil {
+		return false
+	}
+	if channel == models.ChannelEmail && notification.Priority != models.High && preferences.DigestFrequency != models.DigestOff {
+		return false
+	}
+	if channels, ok := preferences.ChannelsFor(notification.Priority, notification.Topic); ok {
+		return slices.Contains(channels, channel)
+	}
+	if channel == models.ChannelEmail {
+		return notification.Priority == models.High || preferences.EmailNotifications
+	}
+	return true
+}
+
+// run attempts a claimed job and then completes, retries or dead-letters it.
+func (s *DeliveryService) run(job *models.DeliveryJob) {
+	channel, ok := s.registry.Get(job.Channel)
+	if !ok {
+		s.fail(job, fmt.Errorf("channel %q is not enabled", job.Channel))
+		return
+	}
+	notification, err := s.notificationRepository.GetNotificationByID(job.NotificationID)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			s.complete(job)
+			return
+		}
+		s.fail(job, err)
+		return
+	}
+	// Expired notifications are no longer worth delivering
+	if isExpired(notification, time.Now()) {
+		s.complete(job)
+		return
+	}
+	delivery := &models.Delivery{
+		DeliveryTarget: models.DeliveryTarget{RecipientID: job.RecipientID, TargetID: job.TargetID},
+		NotificationID: job.NotificationID,
+		Attempt:        job.Attempts,
+	}
+	if err := channel.Send(delivery, notification); err != nil {
+		s.fail(job, err)
+		return
+	}
+	s.complete(job)
+}
+
+func (s *DeliveryService) complete(job *models.DeliveryJob) {
+	if err := s.deliveryRepository.CompleteJob(job.ID); err != nil {
+		log.Println("Error completing delivery job:", err)
+	}
+}
+
+// fail schedules another attempt with backoff, or dead-letters the job once its attempts are used up.
+func (s *DeliveryService) fail(job *models.DeliveryJob, cause error) {
+	if job.Attempts >= job.MaxAttempts {
+		log.Printf("Delivery job %d failed after %d attempts: %v\n", job.ID, job.Attempts, cause)
+		if err := s.deliveryRepository.DeadLetterJob(job.ID, cause.Error()); err != nil {
+			log.Println("Error dead-lettering delivery job:", err)
+		}
+		return
+	}
+	runAt := time.Now().Add(deliveryBackoff(job.Attempts))
+	if err := s.deliveryRepository.RetryJob(job.ID, runAt, cause.Error()); err != nil {
+		log.Println("Error rescheduling delivery job:", err)
+	}
+}
+
+// deliveryBackoff returns the delay before the attempt following attempt. Half of the delay is
+// randomized so that jobs which failed together do not retry together.
+func deliveryBackoff(attempt int) time.Duration {
+	delay := deliveryBackoffMax
+	if attempt < 20 {
+		delay = min(deliveryBackoffBase<<(attempt-1), deliveryBackoffMax)
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func (s *DeliveryService) GetJobs(userID int64, failingOnly bool, page, pageSize int) ([]*models.DeliveryJob, error) {
+	if err := requireAdmin(s.userRepository, userID); err != nil {
+		return nil, err
+	}
+	jobs, err := s.deliveryRepository.GetJobs(failingOnly, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (s *DeliveryService) GetDeadLetterJobs(userID int64, page, pageSize int) ([]*models.DeadLetterJob, error) {
+	if err := requireAdmin(s.userRepository, userID); err != nil {
+		return nil, err
+	}
+	jobs, err := s.deliveryRepository.GetDeadLetterJobs(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (s *DeliveryService) GetDeadLetterJobByID(ID, userID int64) (*models.DeadLetterJob, error) {
+	if err := requireAdmin(s.userRepository, userID); err != nil {
+		return nil, err
+	}
+	job, err := s.deliveryRepository.GetDeadLetterJobByID(ID)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// ReplayDeadLetterJob queues a dead-lettered job again with a fresh set of attempts.
+func (s *DeliveryService) ReplayDeadLetterJob(ID, userID int64) (*models.DeliveryJob, error) {
+	if err := requireAdmin(s.userRepository, userID); err != nil {
+		return nil, err
+	}
+	job, err := s.deliveryRepository.ReplayDeadLetterJob(ID, s.maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *DeliveryService) DeleteDeadLetterJobByID(ID, userID int64) error {
+	if err := requireAdmin(s.userRepository, userID); err != nil {
+		return err
+	}
+	err := s.deliveryRepository.DeleteDeadLetterJobByID(ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// requireAdmin checks that a user has administrator privileges.
+func requireAdmin(userRepository *repositories.UserRepository, userID int64) error {
+	isAdmin, err := userRepository.IsAdmin(userID)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return utils.ErrAdminRequired
+		}
+		return err
+	}
+	if !isAdmin {
+		return utils.ErrAdminRequired
+	}
+	return nil
+}
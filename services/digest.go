@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/channels"
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+const (
+	// digestBatchSize is the number of due digests claimed per query.
+	digestBatchSize = 50
+	// digestLease is how long a claimed digest is hidden from other instances, and the delay before
+	// a digest that failed to send is retried.
+	digestLease = 10 * time.Minute
+	// maxDigestNotifications caps the notifications summarized in a single digest.
+	maxDigestNotifications = 100
+)
+
+type DigestService struct {
+	digestRepository *repositories.DigestRepository
+	userRepository   *repositories.UserRepository
+	emailChannel     *channels.EmailChannel
+}
+
+// NewDigestService creates a digest service. emailChannel is nil when email is not configured, in
+// which case no digests are sent.
+func NewDigestService(digestRepository *repositories.DigestRepository, userRepository *repositories.UserRepository, emailChannel *channels.EmailChannel) *DigestService {
+	return &DigestService{
+		digestRepository: digestRepository,
+		userRepository:   userRepository,
+		emailChannel:     emailChannel,
+	}
+}
+
+// SendDueDigests sends every digest that is due and returns how many were sent.
+func (s *DigestService) SendDueDigests() (int, error) {
+	if s.emailChannel == nil {
+		return 0, nil
+	}
+	sent := 0
+	for {
+		now := time.Now()
+		subscribers, err := s.digestRepository.ClaimDueDigests(now, digestLease, digestBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		for _, subscriber := range subscribers {
+			if s.send(subscriber, now) {
+				sent++
+			}
+		}
+		if len(subscribers) < digestBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// send emails the digest of a single subscriber and schedules the next one. It reports whether a
+// digest was sent. A digest that fails to send is retried once its lease expires.
+func (s *DigestService) send(subscriber *models.DigestSubscriber, now time.Time) bool {
+	periodStart := now.Add(-models.DigestPeriod(subscriber.Frequency))
+	notifications, err := s.digestRepository.GetDigestNotifications(subscriber.UserID, periodStart, maxDigestNotifications)
+	if err != nil {
+		log.Printf("Error collecting digest of user %d: %v\n", subscriber.UserID, err)
+		return false
+	}
+	if len(notifications) == 0 {
+		s.scheduleNext(subscriber, now)
+		return false
+	}
+	recipient, err := s.userRepository.GetUserByID(subscriber.UserID)
+	if err != nil {
+		if !errors.Is(err, utils.ErrNotFound) {
+			log.Printf("Error collecting digest of user %d: %v\n", subscriber.UserID, err)
+		}
+		return false
+	}
+
+	// The digest is recorded before it is sent so that concurrent runs cannot repeat its notifications
+	digest := &models.Digest{
+		UserID:        subscriber.UserID,
+		Frequency:     subscriber.Frequency,
+		PeriodStart:   periodStart.UTC().Format(time.RFC3339),
+		PeriodEnd:     now.UTC().Format(time.RFC3339),
+		Notifications: notifications,
+	}
+	if err := s.digestRepository.CreateDigest(digest); err != nil {
+		log.Printf("Error recording digest of user %d: %v\n", subscriber.UserID, err)
+		return false
+	}
+	if err := s.emailChannel.SendDigest(recipient, digest); err != nil {
+		log.Printf("Error sending digest of user %d: %v\n", subscriber.UserID, err)
+		if err := s.digestRepository.DeleteDigestByID(digest.ID); err != nil {
+			log.Printf("Error discarding digest of user %d: %v\n", subscriber.UserID, err)
+		}
+		return false
+	}
+	s.scheduleNext(subscriber, now)
+	return true
+}
+
+func (s *DigestService) scheduleNext(subscriber *models.DigestSubscriber, now time.Time) {
+	nextAt := models.NextDigestAt(subscriber.Frequency, subscriber.Timezone, now)
+	if nextAt == nil {
+		return
+	}
+	if err := s.digestRepository.ScheduleNextDigest(subscriber.UserID, *nextAt); err != nil {
+		log.Printf("Error scheduling next digest of user %d: %v\n", subscriber.UserID, err)
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// IdempotencyService makes requests sent with an idempotency key take effect once. The first
+// response is stored and replayed to requests repeating the key within the retention window. A key
+// is held for the request it was reserved for during the lease, after which a retry can take it
+// over when the request never completed, e.g. because the server restarted while handling it.
+type IdempotencyService struct {
+	idempotencyRepository *repositories.IdempotencyRepository
+	retention             time.Duration
+	lease                 time.Duration
+}
+
+func NewIdempotencyService(idempotencyRepository *repositories.IdempotencyRepository, retention, lease time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepository: idempotencyRepository,
+		retention:             retention,
+		lease:                 lease,
+	}
+}
+
+// Begin reserves a key for a request. A completed key is returned when the request was made
+// before and its response must be replayed; otherwise the caller handles the request and then
+// completes or releases the key. Reusing a key for a different request is an error.
+func (s *IdempotencyService) Begin(userID int64, key, method, path string, body []byte) (*models.IdempotencyKey, error) {
+	if err := models.ValidateIdempotencyKey(key); err != nil {
+		return nil, err
+	}
+	requestHash := hashRequest(method, path, body)
+
+	now := time.Now()
+	idempotencyKey, reserved, err := s.idempotencyRepository.ReserveIdempotencyKey(userID, key, requestHash, now, now.Add(-s.retention), now.Add(s.lease))
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return idempotencyKey, nil
+	}
+	if idempotencyKey.RequestHash != requestHash {
+		return nil, utils.ErrIdempotencyKeyMismatch
+	}
+	if !idempotencyKey.Completed() {
+		return nil, utils.ErrIdempotencyKeyInUse
+	}
+	return idempotencyKey, nil
+}
+
+// Complete stores the response of the request made with a key.
+func (s *IdempotencyService) Complete(idempotencyKey *models.IdempotencyKey, status int, headers http.Header, body string) error {
+	return s.idempotencyRepository.SaveIdempotentResponse(idempotencyKey.ID, idempotencyKey.LockedUntil, status, headers, body)
+}
+
+// Release gives up a key whose request failed, so that it can be retried with the same key.
+func (s *IdempotencyService) Release(idempotencyKey *models.IdempotencyKey) error {
+	return s.idempotencyRepository.DeleteIdempotencyKeyByID(idempotencyKey.ID, idempotencyKey.LockedUntil)
+}
+
+// PurgeExpiredIdempotencyKeys removes the keys that are past the retention window and returns how many were removed.
+func (s *IdempotencyService) PurgeExpiredIdempotencyKeys() (int64, error) {
+	return s.idempotencyRepository.DeleteExpiredIdempotencyKeys(time.Now().Add(-s.retention))
+}
+
+// hashRequest fingerprints a request. JSON bodies are hashed in canonical form, so that
+// retries which only reorder fields or change whitespace are recognised as the same request.
+func hashRequest(method, path string, body []byte) string {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err == nil {
+		if canonical, err := json.Marshal(value); err == nil {
+			body = canonical
+		}
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -1,33 +1,256 @@
 package services
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
 	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/realtime"
 	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/templating"
+	"github.com/akinolaemmanuel49/notify-api/utils"
 )
 
+// missedNotificationsLimit caps how many notifications are replayed to a reconnecting stream.
+const missedNotificationsLimit = 100
+
+// releaseBatchSize is the number of scheduled notifications released per query.
+const releaseBatchSize = 100
+
+// purgeBatchSize is the number of expired notifications removed per query.
+const purgeBatchSize = 500
+
 type NotificationService struct {
 	notificationRepository *repositories.NotificationRepository
+	templateRepository     *repositories.TemplateRepository
+	userRepository         *repositories.UserRepository
+	hub                    *realtime.Hub
+	deliveryService        *DeliveryService
 }
 
-func NewNotificationService(notificationRepository *repositories.NotificationRepository) *NotificationService {
+func NewNotificationService(notificationRepository *repositories.NotificationRepository, templateRepository *repositories.TemplateRepository, userRepository *repositories.UserRepository, hub *realtime.Hub, deliveryService *DeliveryService) *NotificationService {
 	return &NotificationService{
 		notificationRepository: notificationRepository,
+		templateRepository:     templateRepository,
+		userRepository:         userRepository,
+		hub:                    hub,
+		deliveryService:        deliveryService,
 	}
 }
 
-func (s *NotificationService) CreateNotification(notificationInput *models.NotificationInput, publisherID int64) error {
+func (s *NotificationService) CreateNotification(notificationInput *models.NotificationInput, publisherID int64) (*models.Notification, error) {
+	if err := s.RequireVerifiedPublisher(publisherID); err != nil {
+		return nil, err
+	}
+	if notificationInput.Template != "" {
+		if err := s.renderTemplate(notificationInput, publisherID); err != nil {
+			return nil, err
+		}
+	}
 	if err := notificationInput.Priority.Validate(); err != nil {
+		return nil, err
+	}
+	translations, err := notificationInput.Translations.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	notificationInput.Translations = translations
+	if notificationInput.Topic != "" {
+		if err := models.ValidateTopicName(notificationInput.Topic); err != nil {
+			return nil, err
+		}
+	}
+	// The repository decides whether the notification is released with the same clock, so that it
+	// is dispatched either here or by the scheduler but never by both
+	now := time.Now()
+	if err := notificationInput.ValidateExpiry(now); err != nil {
+		return nil, err
+	}
+	notification, err := s.notificationRepository.CreateNotification(notificationInput, publisherID, now)
+	if err != nil {
+		return nil, err
+	}
+	// Scheduled notifications are dispatched by the scheduler once they are due
+	if !notificationInput.IsScheduled(now) {
+		s.dispatch(notification.ID)
+	}
+	return notification, nil
+}
+
+// RequireVerifiedPublisher checks that a user has verified their email address, which is required to publish notifications.
+func (s *NotificationService) RequireVerifiedPublisher(publisherID int64) error {
+	verified, err := s.userRepository.IsEmailVerified(publisherID)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return utils.ErrEmailNotVerified
+	}
+	return nil
+}
+
+// renderTemplate fills in the title, message and translations of a notification from one of the
+// publisher's templates.
+func (s *NotificationService) renderTemplate(notificationInput *models.NotificationInput, publisherID int64) error {
+	if notificationInput.Title != "" || notificationInput.Message != "" || len(notificationInput.Translations) > 0 {
+		return utils.ErrTemplateConflict
+	}
+	template, err := s.templateRepository.GetTemplateByName(publisherID, notificationInput.Template)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return fmt.Errorf("%w: %s", utils.ErrTemplateNotFound, notificationInput.Template)
+		}
+		return err
+	}
+	title, err := templating.Render(template.Title, notificationInput.Variables)
+	if err != nil {
 		return err
 	}
-	err := s.notificationRepository.CreateNotification(notificationInput, publisherID)
+	message, err := templating.Render(template.Message, notificationInput.Variables)
+	if err != nil {
+		return err
+	}
+	translations := models.Translations{}
+	for locale, translation := range template.Translations {
+		title, err := templating.Render(translation.Title, notificationInput.Variables)
+		if err != nil {
+			return err
+		}
+		message, err := templating.Render(translation.Message, notificationInput.Variables)
+		if err != nil {
+			return err
+		}
+		translations[locale] = models.Translation{Title: title, Message: message}
+	}
+	notificationInput.Title = title
+	notificationInput.Message = message
+	notificationInput.Translations = translations
+	return nil
+}
+
+// ReleaseScheduledNotifications releases every scheduled notification that is due and dispatches it.
+// It returns the number of notifications released.
+func (s *NotificationService) ReleaseScheduledNotifications() (int, error) {
+	released := 0
+	for {
+		IDs, err := s.notificationRepository.ReleaseDueNotifications(releaseBatchSize)
+		if err != nil {
+			return released, err
+		}
+		for _, ID := range IDs {
+			s.dispatch(ID)
+		}
+		released += len(IDs)
+		if len(IDs) < releaseBatchSize {
+			return released, nil
+		}
+	}
+}
+
+// dispatch pushes a newly created notification to the live connections of its recipients and queues
+// its outbound deliveries.
+// The notification is already stored, so failures are logged rather than returned.
+func (s *NotificationService) dispatch(ID int64) {
+	notification, err := s.notificationRepository.GetNotificationByID(ID)
+	if err != nil {
+		log.Println("Error dispatching notification:", err)
+		return
+	}
+	recipientIDs, err := s.notificationRepository.GetRecipientIDs(ID)
+	if err != nil {
+		log.Println("Error dispatching notification:", err)
+		return
+	}
+	s.hub.Publish(notification, recipientIDs)
+	if err := s.deliveryService.Enqueue(notification, recipientIDs); err != nil {
+		log.Println("Error queueing notification deliveries:", err)
+	}
+}
+
+// PurgeExpiredNotifications removes every expired notification in batches, archiving them when archive is true.
+// It returns the number of notifications removed.
+func (s *NotificationService) PurgeExpiredNotifications(archive bool) (int64, error) {
+	var purged int64
+	for {
+		count, err := s.notificationRepository.PurgeExpiredNotifications(archive, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		purged += count
+		if count < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// Subscribe registers a live connection for a recipient. It returns nil when the server is shutting down.
+func (s *NotificationService) Subscribe(recipientID int64) *realtime.Client {
+	client := s.hub.Register(recipientID)
+	if client != nil {
+		client.Locale = s.getLocale(recipientID)
+	}
+	return client
+}
+
+// getLocale retrieves the locale of a recipient. Notifications are shown in their default language
+// when it cannot be retrieved.
+func (s *NotificationService) getLocale(recipientID int64) string {
+	locale, err := s.userRepository.GetLocale(recipientID)
 	if err != nil {
+		log.Println("Error retrieving recipient locale:", err)
+		return ""
+	}
+	return locale
+}
+
+// Unsubscribe releases a live connection once it has been torn down.
+func (s *NotificationService) Unsubscribe(client *realtime.Client) {
+	s.hub.Release(client)
+}
+
+// FollowPublisher makes a live connection receive the notifications a publisher posts to a topic or
+// addresses to no one.
+func (s *NotificationService) FollowPublisher(client *realtime.Client, publisherID int64) {
+	s.hub.Subscribe(client, realtime.PublisherSubscription(publisherID))
+}
+
+func (s *NotificationService) UnfollowPublisher(client *realtime.Client, publisherID int64) {
+	s.hub.Unsubscribe(client, realtime.PublisherSubscription(publisherID))
+}
+
+// FollowTopic makes a live connection receive every notification posted to a topic.
+func (s *NotificationService) FollowTopic(client *realtime.Client, topic string) error {
+	if err := models.ValidateTopicName(topic); err != nil {
 		return err
 	}
+	s.hub.Subscribe(client, realtime.TopicSubscription(topic))
 	return nil
 }
 
-func (s *NotificationService) GetNotificationByID(id int64) (*models.Notification, error) {
-	notification, err := s.notificationRepository.GetNotificationByID(id)
+func (s *NotificationService) UnfollowTopic(client *realtime.Client, topic string) {
+	s.hub.Unsubscribe(client, realtime.TopicSubscription(topic))
+}
+
+// GetMissedNotifications retrieves the notifications a recipient has not seen since lastEventID.
+func (s *NotificationService) GetMissedNotifications(recipientID, lastEventID int64) ([]*models.Notification, error) {
+	notifications, err := s.notificationRepository.GetInboxNotificationsAfter(recipientID, lastEventID, missedNotificationsLimit)
+	if err != nil {
+		return nil, err
+	}
+	locale := s.getLocale(recipientID)
+	for i, notification := range notifications {
+		notifications[i] = notification.Localize(locale)
+	}
+	return notifications, nil
+}
+
+// GetNotificationByID retrieves a notification the user published, is a recipient of or subscribes
+// to the topic of.
+func (s *NotificationService) GetNotificationByID(id, userID int64) (*models.Notification, error) {
+	notification, err := s.notificationRepository.GetAccessibleNotificationByID(id, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -42,8 +265,54 @@ func (s *NotificationService) GetOwnNotifications(ID int64, page, pageSize int)
 	return notifications, nil
 }
 
-func (s *NotificationService) GetAllNotifications(page, pageSize int) ([]*models.Notification, error) {
-	notifications, err := s.notificationRepository.GetAllNotifications(page, pageSize)
+func (s *NotificationService) GetInbox(recipientID int64, unreadOnly bool, page, pageSize int) ([]*models.InboxNotification, error) {
+	notifications, err := s.notificationRepository.GetInboxNotifications(recipientID, unreadOnly, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	locale := s.getLocale(recipientID)
+	for _, notification := range notifications {
+		notification.Notification = *notification.Localize(locale)
+	}
+	return notifications, nil
+}
+
+func (s *NotificationService) MarkNotificationAsRead(ID, recipientID int64) error {
+	err := s.notificationRepository.MarkNotificationAsRead(ID, recipientID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *NotificationService) AcknowledgeNotification(ID, recipientID int64) error {
+	err := s.notificationRepository.AcknowledgeNotification(ID, recipientID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *NotificationService) MarkAllNotificationsAsRead(recipientID int64) (int64, error) {
+	count, err := s.notificationRepository.MarkAllNotificationsAsRead(recipientID)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *NotificationService) GetUnreadCount(recipientID int64) (int64, error) {
+	count, err := s.notificationRepository.GetUnreadCount(recipientID)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetAllNotifications retrieves the notifications the user published, is a recipient of or
+// subscribes to the topic of.
+func (s *NotificationService) GetAllNotifications(userID int64, page, pageSize int) ([]*models.Notification, error) {
+	notifications, err := s.notificationRepository.GetAllNotifications(userID, page, pageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +331,32 @@ func (s *NotificationService) UpdateNotificationByID(ID, publisherID int64, fiel
 		}
 		fields["priority"] = priority
 	}
+	if ttlField, ok := fields["ttl"]; ok {
+		// A TTL on update counts from now
+		ttl, ok := ttlField.(float64)
+		if !ok || ttl <= 0 {
+			return utils.ErrInvalidExpiry
+		}
+		delete(fields, "ttl")
+		fields["expires_at"] = time.Now().UTC().Add(time.Duration(ttl) * time.Second).Format(time.RFC3339)
+	}
+	if translationsField, ok := fields["translations"]; ok {
+		// Translations are replaced as a whole
+		encoded, err := json.Marshal(translationsField)
+		if err != nil {
+			return err
+		}
+		var translations models.Translations
+		if err := json.Unmarshal(encoded, &translations); err != nil {
+			return utils.ErrInvalidTranslations
+		}
+		if fields["translations"], err = translations.Normalize(); err != nil {
+			return err
+		}
+	}
+	if err := s.validateSchedule(ID, publisherID, fields); err != nil {
+		return err
+	}
 	err := s.notificationRepository.UpdateNotificationByID(ID, publisherID, fields)
 	if err != nil {
 		return err
@@ -69,6 +364,60 @@ func (s *NotificationService) UpdateNotificationByID(ID, publisherID int64, fiel
 	return nil
 }
 
+// validateSchedule checks the send_at and expires_at fields of a notification update and replaces
+// them with their normalized times. send_at must be in the future and can only change while the
+// notification is still waiting to be sent, and the notification must expire after it is sent.
+func (s *NotificationService) validateSchedule(ID, publisherID int64, fields map[string]interface{}) error {
+	sendAtField, sendAtChanged := fields["send_at"]
+	expiresAtField, expiresAtChanged := fields["expires_at"]
+	if !sendAtChanged && !expiresAtChanged {
+		return nil
+	}
+	sendAt, expiresAt, dispatched, err := s.notificationRepository.GetNotificationSchedule(ID, publisherID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if sendAtChanged {
+		if dispatched {
+			return utils.ErrNotificationDispatched
+		}
+		value, ok := sendAtField.(string)
+		if !ok {
+			return utils.ErrInvalidSendAt
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil || !parsed.After(now) {
+			return utils.ErrInvalidSendAt
+		}
+		sendAt = &parsed
+		fields["send_at"] = parsed.UTC().Format(time.RFC3339)
+	}
+	if expiresAtChanged {
+		// A null expires_at makes the notification never expire
+		expiresAt = nil
+		if expiresAtField != nil {
+			value, ok := expiresAtField.(string)
+			if !ok {
+				return utils.ErrInvalidExpiry
+			}
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return utils.ErrInvalidExpiry
+			}
+			expiresAt = &parsed
+			fields["expires_at"] = parsed.UTC().Format(time.RFC3339)
+		}
+	}
+
+	schedule := models.NotificationInput{ExpiresAt: expiresAt}
+	if !dispatched {
+		schedule.SendAt = sendAt
+	}
+	return schedule.ValidateExpiry(now)
+}
+
 func (s *NotificationService) DeleteNotificationByID(ID, publisherID int64) error {
 	err := s.notificationRepository.DeleteNotificationByID(ID, publisherID)
 	if err != nil {
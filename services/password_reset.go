@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/channels"
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/passwords"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// passwordResetTokenBytes is the number of random bytes in a password reset token.
+const passwordResetTokenBytes = 32
+
+type PasswordResetService struct {
+	passwordResetRepository *repositories.PasswordResetRepository
+	userRepository          *repositories.UserRepository
+	emailChannel            *channels.EmailChannel
+	passwordPolicy          *passwords.Policy
+	tokenTTL                time.Duration
+}
+
+// NewPasswordResetService creates a password reset service. emailChannel is nil when email is not
+// configured, in which case passwords cannot be reset.
+func NewPasswordResetService(passwordResetRepository *repositories.PasswordResetRepository, userRepository *repositories.UserRepository, emailChannel *channels.EmailChannel, passwordPolicy *passwords.Policy, tokenTTL time.Duration) *PasswordResetService {
+	return &PasswordResetService{
+		passwordResetRepository: passwordResetRepository,
+		userRepository:          userRepository,
+		emailChannel:            emailChannel,
+		passwordPolicy:          passwordPolicy,
+		tokenTTL:                tokenTTL,
+	}
+}
+
+// RequestPasswordReset emails a single-use reset token to the account an email address belongs to.
+// The outcome is the same whether or not there is such an account, and the email is sent in the
+// background, so neither the response nor its timing reveals which addresses have accounts.
+func (s *PasswordResetService) RequestPasswordReset(email string) error {
+	if s.emailChannel == nil {
+		return utils.ErrPasswordResetUnavailable
+	}
+	user, err := s.userRepository.GetUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	go func() {
+		if err := s.sendPasswordReset(user); err != nil {
+			log.Println("Error sending password reset email:", err)
+		}
+	}()
+	return nil
+}
+
+func (s *PasswordResetService) sendPasswordReset(user *models.UserProfile) error {
+	token, err := utils.GenerateSecret(passwordResetTokenBytes)
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(s.tokenTTL)
+	err = s.passwordResetRepository.CreatePasswordResetToken(user.ID, utils.HashToken(token), expiresAt)
+	if err != nil {
+		return err
+	}
+	return s.emailChannel.SendPasswordReset(user, token, expiresAt)
+}
+
+// ConfirmPasswordReset sets a new password for the account a reset token was sent to and uses the
+// token up. Tokens issued to the user before stop working.
+func (s *PasswordResetService) ConfirmPasswordReset(confirmation *models.PasswordResetConfirmation) error {
+	if confirmation.Token == "" {
+		return utils.ErrInvalidPasswordResetToken
+	}
+	if err := s.passwordPolicy.Validate(confirmation.Password); err != nil {
+		return err
+	}
+	passwordHash, err := utils.GenerateHashPassword(confirmation.Password)
+	if err != nil {
+		return err
+	}
+	userID, err := s.passwordResetRepository.ResetPassword(utils.HashToken(confirmation.Token), passwordHash, time.Now())
+	if err != nil {
+		return err
+	}
+	log.Printf("Password of user %d was reset\n", userID)
+	return nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"github.com/akinolaemmanuel49/notify-api/channels"
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+type PreferenceService struct {
+	preferenceRepository *repositories.PreferenceRepository
+	registry             *channels.Registry
+}
+
+func NewPreferenceService(preferenceRepository *repositories.PreferenceRepository, registry *channels.Registry) *PreferenceService {
+	return &PreferenceService{
+		preferenceRepository: preferenceRepository,
+		registry:             registry,
+	}
+}
+
+func (s *PreferenceService) GetPreferences(ID, claimsID int64) (*models.Preferences, error) {
+	if claimsID != ID {
+		return nil, utils.ErrForbidden
+	}
+	preferences, err := s.preferenceRepository.GetPreferences(ID)
+	if err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences replaces the preferences of a user. Only channels enabled on this server can be chosen.
+func (s *PreferenceService) UpdatePreferences(ID, claimsID int64, preferences *models.Preferences) error {
+	if claimsID != ID {
+		return utils.ErrForbidden
+	}
+	err := preferences.Validate(func(name string) bool {
+		_, ok := s.registry.Get(name)
+		return ok
+	})
+	if err != nil {
+		return err
+	}
+	if preferences.Priorities == nil {
+		preferences.Priorities = map[string][]string{}
+	}
+	if preferences.Topics == nil {
+		preferences.Topics = map[string][]string{}
+	}
+	err = s.preferenceRepository.SavePreferences(ID, preferences)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/cron"
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+const (
+	// recurrenceBatchSize is the number of due recurrences claimed per query.
+	recurrenceBatchSize = 50
+	// recurrenceLease is how long a claimed recurrence is hidden from other instances.
+	recurrenceLease = 5 * time.Minute
+	// recurrenceGracePeriod is how late a firing may be processed before it counts as missed.
+	recurrenceGracePeriod = 2 * time.Minute
+	// maxCatchUpFirings caps the notifications generated for a single recurrence in one run.
+	maxCatchUpFirings = 100
+)
+
+type RecurrenceService struct {
+	recurrenceRepository *repositories.RecurrenceRepository
+	notificationService  *NotificationService
+	defaultCatchUpPolicy string
+}
+
+func NewRecurrenceService(recurrenceRepository *repositories.RecurrenceRepository, notificationService *NotificationService, defaultCatchUpPolicy string) *RecurrenceService {
+	return &RecurrenceService{
+		recurrenceRepository: recurrenceRepository,
+		notificationService:  notificationService,
+		defaultCatchUpPolicy: defaultCatchUpPolicy,
+	}
+}
+
+func (s *RecurrenceService) CreateRecurrence(recurrenceInput *models.RecurrenceInput, publisherID int64) error {
+	if err := s.notificationService.RequireVerifiedPublisher(publisherID); err != nil {
+		return err
+	}
+	nextRunAt, err := s.prepare(recurrenceInput)
+	if err != nil {
+		return err
+	}
+	err = s.recurrenceRepository.CreateRecurrence(recurrenceInput, publisherID, nextRunAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *RecurrenceService) GetRecurrenceByID(ID, publisherID int64) (*models.Recurrence, error) {
+	recurrence, err := s.recurrenceRepository.GetRecurrenceByID(ID)
+	if err != nil {
+		return nil, err
+	}
+	if recurrence.PublisherID != publisherID {
+		return nil, utils.ErrForbidden
+	}
+	return recurrence, nil
+}
+
+func (s *RecurrenceService) GetOwnRecurrences(publisherID int64, page, pageSize int) ([]*models.Recurrence, error) {
+	recurrences, err := s.recurrenceRepository.GetOwnRecurrences(publisherID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return recurrences, nil
+}
+
+func (s *RecurrenceService) UpdateRecurrenceByID(ID, publisherID int64, recurrenceInput *models.RecurrenceInput) error {
+	if _, err := s.GetRecurrenceByID(ID, publisherID); err != nil {
+		return err
+	}
+	nextRunAt, err := s.prepare(recurrenceInput)
+	if err != nil {
+		return err
+	}
+	err = s.recurrenceRepository.UpdateRecurrenceByID(ID, recurrenceInput, nextRunAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *RecurrenceService) DeleteRecurrenceByID(ID, publisherID int64) error {
+	if _, err := s.GetRecurrenceByID(ID, publisherID); err != nil {
+		return err
+	}
+	err := s.recurrenceRepository.DeleteRecurrenceByID(ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// prepare validates a recurrence definition, fills in defaults and returns its first firing time.
+func (s *RecurrenceService) prepare(recurrenceInput *models.RecurrenceInput) (time.Time, error) {
+	if err := recurrenceInput.Priority.Validate(); err != nil {
+		return time.Time{}, err
+	}
+	if recurrenceInput.Topic != "" {
+		if err := models.ValidateTopicName(recurrenceInput.Topic); err != nil {
+			return time.Time{}, err
+		}
+	}
+	if recurrenceInput.Timezone == "" {
+		recurrenceInput.Timezone = "UTC"
+	}
+	if recurrenceInput.CatchUpPolicy == "" {
+		recurrenceInput.CatchUpPolicy = s.defaultCatchUpPolicy
+	}
+	if err := models.ValidateCatchUpPolicy(recurrenceInput.CatchUpPolicy); err != nil {
+		return time.Time{}, err
+	}
+	if recurrenceInput.Active == nil {
+		active := true
+		recurrenceInput.Active = &active
+	}
+	if recurrenceInput.Recipients == nil {
+		recurrenceInput.Recipients = []int64{}
+	}
+
+	schedule, location, err := parseRecurrenceSchedule(recurrenceInput.CronExpression, recurrenceInput.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	nextRunAt, err := schedule.Next(time.Now().In(location))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", utils.ErrInvalidCronExpression, err.Error())
+	}
+	return nextRunAt, nil
+}
+
+// FireDueRecurrences generates notifications for every recurrence that is due and returns how many were generated.
+func (s *RecurrenceService) FireDueRecurrences() (int, error) {
+	fired := 0
+	for {
+		now := time.Now()
+		recurrences, err := s.recurrenceRepository.ClaimDueRecurrences(now, recurrenceLease, recurrenceBatchSize)
+		if err != nil {
+			return fired, err
+		}
+		for _, recurrence := range recurrences {
+			fired += s.fire(recurrence, now)
+		}
+		if len(recurrences) < recurrenceBatchSize {
+			return fired, nil
+		}
+	}
+}
+
+// fire generates the notifications of a single due recurrence according to its catch-up policy
+// and schedules its next firing. It returns how many notifications were generated.
+func (s *RecurrenceService) fire(recurrence *models.Recurrence, now time.Time) int {
+	schedule, location, err := parseRecurrenceSchedule(recurrence.CronExpression, recurrence.Timezone)
+	if err != nil {
+		log.Printf("Error parsing schedule of recurrence %d: %v\n", recurrence.ID, err)
+		return 0
+	}
+	nextRunAt, err := time.Parse(time.RFC3339, recurrence.NextRunAt)
+	if err != nil {
+		log.Printf("Error parsing next run of recurrence %d: %v\n", recurrence.ID, err)
+		return 0
+	}
+
+	// Collect every firing between the scheduled run and now
+	firings := []time.Time{}
+	for firing := nextRunAt.In(location); !firing.After(now) && len(firings) < maxCatchUpFirings; {
+		firings = append(firings, firing)
+		firing, err = schedule.Next(firing)
+		if err != nil {
+			break
+		}
+	}
+
+	switch recurrence.CatchUpPolicy {
+	case models.CatchUpSkip:
+		onTime := []time.Time{}
+		for _, firing := range firings {
+			if now.Sub(firing) <= recurrenceGracePeriod {
+				onTime = append(onTime, firing)
+			}
+		}
+		firings = onTime
+	case models.CatchUpOnce:
+		if len(firings) > 1 {
+			firings = firings[len(firings)-1:]
+		}
+	}
+
+	var lastRunAt *time.Time
+	for i, firing := range firings {
+		notificationInput := models.NotificationInput{
+			Title:      recurrence.Title,
+			Message:    recurrence.Message,
+			Priority:   recurrence.Priority,
+			Recipients: recurrence.Recipients,
+			Topic:      recurrence.Topic,
+		}
+		_, err := s.notificationService.CreateNotification(&notificationInput, recurrence.PublisherID)
+		if errors.Is(err, utils.ErrRecipientNotFound) || errors.Is(err, utils.ErrEmailNotVerified) {
+			// Retrying cannot fix a deleted recipient or an unverified publisher, so the firing is dropped
+			log.Printf("Error firing recurrence %d: %v\n", recurrence.ID, err)
+			continue
+		}
+		if err != nil {
+			// Resume from the failed firing on the next run
+			log.Printf("Error firing recurrence %d: %v\n", recurrence.ID, err)
+			s.complete(recurrence.ID, lastRunAt, firing)
+			return i
+		}
+		firedAt := firing
+		lastRunAt = &firedAt
+	}
+
+	next, err := schedule.Next(now.In(location))
+	if err != nil {
+		// The expression no longer fires, park the recurrence far in the future
+		next = now.AddDate(100, 0, 0)
+	}
+	s.complete(recurrence.ID, lastRunAt, next)
+	return len(firings)
+}
+
+func (s *RecurrenceService) complete(ID int64, lastRunAt *time.Time, nextRunAt time.Time) {
+	err := s.recurrenceRepository.CompleteRecurrenceRun(ID, lastRunAt, nextRunAt)
+	if err != nil {
+		log.Printf("Error completing run of recurrence %d: %v\n", ID, err)
+	}
+}
+
+func parseRecurrenceSchedule(expression, timezone string) (*cron.Schedule, *time.Location, error) {
+	schedule, err := cron.Parse(expression)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInvalidCronExpression, err.Error())
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInvalidTimezone, timezone)
+	}
+	return schedule, location, nil
+}
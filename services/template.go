@@ -0,0 +1,89 @@
+package services
+
+import (
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/templating"
+)
+
+type TemplateService struct {
+	templateRepository *repositories.TemplateRepository
+}
+
+func NewTemplateService(templateRepository *repositories.TemplateRepository) *TemplateService {
+	return &TemplateService{
+		templateRepository: templateRepository,
+	}
+}
+
+func (s *TemplateService) CreateTemplate(templateInput *models.TemplateInput, publisherID int64) (*models.Template, error) {
+	if err := s.prepare(templateInput); err != nil {
+		return nil, err
+	}
+	template, err := s.templateRepository.CreateTemplate(templateInput, publisherID)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *TemplateService) GetTemplateByName(publisherID int64, name string) (*models.Template, error) {
+	template, err := s.templateRepository.GetTemplateByName(publisherID, name)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *TemplateService) GetOwnTemplates(publisherID int64, page, pageSize int) ([]*models.Template, error) {
+	templates, err := s.templateRepository.GetOwnTemplates(publisherID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// UpdateTemplateByName replaces a template. An empty name keeps the current one.
+func (s *TemplateService) UpdateTemplateByName(publisherID int64, name string, templateInput *models.TemplateInput) error {
+	if templateInput.Name == "" {
+		templateInput.Name = name
+	}
+	if err := s.prepare(templateInput); err != nil {
+		return err
+	}
+	err := s.templateRepository.UpdateTemplateByName(publisherID, name, templateInput)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *TemplateService) DeleteTemplateByName(publisherID int64, name string) error {
+	err := s.templateRepository.DeleteTemplateByName(publisherID, name)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// prepare validates the name of a template and checks that its title, message and translations parse.
+func (s *TemplateService) prepare(templateInput *models.TemplateInput) error {
+	if err := models.ValidateTemplateName(templateInput.Name); err != nil {
+		return err
+	}
+	translations, err := templateInput.Translations.Normalize()
+	if err != nil {
+		return err
+	}
+	templateInput.Translations = translations
+	sources := []string{templateInput.Title, templateInput.Message}
+	for _, translation := range translations {
+		sources = append(sources, translation.Title, translation.Message)
+	}
+	for _, source := range sources {
+		if err := templating.Validate(source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
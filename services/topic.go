@@ -0,0 +1,67 @@
+package services
+
+import (
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+)
+
+type TopicService struct {
+	topicRepository *repositories.TopicRepository
+}
+
+func NewTopicService(topicRepository *repositories.TopicRepository) *TopicService {
+	return &TopicService{
+		topicRepository: topicRepository,
+	}
+}
+
+func (s *TopicService) CreateTopic(topicInput *models.TopicInput, creatorID int64) error {
+	if err := models.ValidateTopicName(topicInput.Name); err != nil {
+		return err
+	}
+	err := s.topicRepository.CreateTopic(topicInput, creatorID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *TopicService) GetTopicByName(name string) (*models.Topic, error) {
+	topic, err := s.topicRepository.GetTopicByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return topic, nil
+}
+
+func (s *TopicService) GetAllTopics(page, pageSize int) ([]*models.Topic, error) {
+	topics, err := s.topicRepository.GetAllTopics(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return topics, nil
+}
+
+func (s *TopicService) Subscribe(topicName string, userID int64) error {
+	err := s.topicRepository.CreateSubscription(topicName, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *TopicService) Unsubscribe(topicName string, userID int64) error {
+	err := s.topicRepository.DeleteSubscription(topicName, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *TopicService) GetSubscriptions(userID int64) ([]*models.Subscription, error) {
+	subscriptions, err := s.topicRepository.GetSubscriptions(userID)
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
@@ -1,35 +1,58 @@
 package services
 
 import (
+	"log"
+	"strings"
+	"time"
+
 	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/passwords"
 	"github.com/akinolaemmanuel49/notify-api/repositories"
 	"github.com/akinolaemmanuel49/notify-api/utils"
 )
 
 type UserService struct {
-	userRepository *repositories.UserRepository
+	userRepository      *repositories.UserRepository
+	verificationService *VerificationService
+	passwordPolicy      *passwords.Policy
 }
 
-func NewUserService(userRepository *repositories.UserRepository) *UserService {
+func NewUserService(userRepository *repositories.UserRepository, verificationService *VerificationService, passwordPolicy *passwords.Policy) *UserService {
 	return &UserService{
-		userRepository: userRepository,
+		userRepository:      userRepository,
+		verificationService: verificationService,
+		passwordPolicy:      passwordPolicy,
 	}
 }
 
-func (s *UserService) CreateUser(userInputWithPassword *models.UserInputWithPassword) error {
+func (s *UserService) CreateUser(userInputWithPassword *models.UserInputWithPassword) (*models.UserProfile, error) {
 	userInput := models.UserInput{
 		FirstName: userInputWithPassword.FirstName,
 		LastName:  userInputWithPassword.LastName,
 		Email:     userInputWithPassword.Email,
+		Timezone:  userInputWithPassword.Timezone,
+	}
+	if userInput.Timezone == "" {
+		userInput.Timezone = "UTC"
+	}
+	if err := models.ValidateTimezone(userInput.Timezone); err != nil {
+		return nil, err
 	}
 
 	password := userInputWithPassword.Password
+	if err := s.passwordPolicy.Validate(password); err != nil {
+		return nil, err
+	}
 
-	err := s.userRepository.CreateUser(&userInput, password)
+	user, err := s.userRepository.CreateUser(&userInput, password)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	// The account exists either way, a verification email that failed to send can be requested again
+	if err := s.verificationService.SendVerification(user); err != nil {
+		log.Println("Error sending verification email:", err)
+	}
+	return user, nil
 }
 
 func (s *UserService) GetUserByID(id int64) (*models.UserProfile, error) {
@@ -53,7 +76,59 @@ func (s *UserService) UpdateUserByID(ID, claimsID int64, fields map[string]inter
 		// utils.RespondWithError(w, "You are not permitted to modify this resource", http.StatusForbidden)
 		return utils.ErrForbidden
 	}
-	err := s.userRepository.UpdateUserByID(ID, fields)
+	if err := validateUserFields(fields); err != nil {
+		return err
+	}
+	digestChanged, nextDigestAt, err := s.nextDigest(ID, fields)
+	if err != nil {
+		return err
+	}
+	emailChanged, err := s.emailChanged(ID, fields)
+	if err != nil {
+		return err
+	}
+	err = s.userRepository.UpdateUserByID(ID, fields)
+	if err != nil {
+		return err
+	}
+	if digestChanged {
+		if err := s.userRepository.ScheduleDigest(ID, nextDigestAt); err != nil {
+			return err
+		}
+	}
+	if emailChanged {
+		user, err := s.userRepository.GetUserByID(ID)
+		if err != nil {
+			return err
+		}
+		if err := s.verificationService.SendVerification(user); err != nil {
+			log.Println("Error sending verification email:", err)
+		}
+	}
+	return nil
+}
+
+// ChangePassword replaces the password of a user after checking their current one. Tokens issued
+// to the user before the change stop working.
+func (s *UserService) ChangePassword(ID, claimsID int64, passwordChange *models.AuthPasswordChange) error {
+	if claimsID != ID {
+		return utils.ErrForbidden
+	}
+	passwordHash, err := s.userRepository.GetPasswordHash(ID)
+	if err != nil {
+		return err
+	}
+	if !utils.VerifyPassword(passwordHash, passwordChange.OldPassword) {
+		return utils.ErrIncorrectPassword
+	}
+	if err := s.passwordPolicy.Validate(passwordChange.NewPassword); err != nil {
+		return err
+	}
+	newPasswordHash, err := utils.GenerateHashPassword(passwordChange.NewPassword)
+	if err != nil {
+		return err
+	}
+	err = s.userRepository.UpdatePassword(ID, newPasswordHash, time.Now())
 	if err != nil {
 		return err
 	}
@@ -71,3 +146,90 @@ func (s *UserService) DeleteUserByID(ID, claimsID int64) error {
 	}
 	return nil
 }
+
+// emailChanged reports whether an update changes the email address of a user, which leaves the new
+// address unverified.
+func (s *UserService) emailChanged(ID int64, fields map[string]interface{}) (bool, error) {
+	email, ok := fields["email"].(string)
+	if !ok {
+		return false, nil
+	}
+	user, err := s.userRepository.GetUserByID(ID)
+	if err != nil {
+		return false, err
+	}
+	return !strings.EqualFold(user.Email, email), nil
+}
+
+// nextDigest returns when the next digest is due when a user update changes the digest frequency
+// or the timezone digests are scheduled in, and reports whether it does.
+func (s *UserService) nextDigest(ID int64, fields map[string]interface{}) (bool, *time.Time, error) {
+	frequencyField, frequencyChanged := fields["digest_frequency"]
+	timezoneField, timezoneChanged := fields["timezone"]
+	if !frequencyChanged && !timezoneChanged {
+		return false, nil, nil
+	}
+	user, err := s.userRepository.GetUserByID(ID)
+	if err != nil {
+		return false, nil, err
+	}
+	frequency, timezone := user.DigestFrequency, user.Timezone
+	if frequencyChanged {
+		frequency = frequencyField.(string)
+	}
+	if timezoneChanged {
+		timezone = timezoneField.(string)
+	}
+	return true, models.NextDigestAt(frequency, timezone, time.Now()), nil
+}
+
+// validateUserFields checks the timezone, locale, quiet hours and digest frequency of a user update.
+func validateUserFields(fields map[string]interface{}) error {
+	if timezoneField, ok := fields["timezone"]; ok {
+		timezone, ok := timezoneField.(string)
+		if !ok {
+			return utils.ErrInvalidTimezone
+		}
+		if err := models.ValidateTimezone(timezone); err != nil {
+			return err
+		}
+	}
+	if localeField, ok := fields["locale"]; ok {
+		locale, ok := localeField.(string)
+		if !ok {
+			return utils.ErrInvalidLocale
+		}
+		// An empty locale shows notifications in their default language
+		locale = models.NormalizeLocale(locale)
+		if locale != "" {
+			if err := models.ValidateLocale(locale); err != nil {
+				return err
+			}
+		}
+		fields["locale"] = locale
+	}
+	if frequencyField, ok := fields["digest_frequency"]; ok {
+		frequency, ok := frequencyField.(string)
+		if !ok {
+			return utils.ErrInvalidDigestFrequency
+		}
+		if err := models.ValidateDigestFrequency(frequency); err != nil {
+			return err
+		}
+	}
+	for _, key := range []string{"quiet_hours_start", "quiet_hours_end"} {
+		clockField, ok := fields[key]
+		// A null value clears the quiet hours
+		if !ok || clockField == nil {
+			continue
+		}
+		clock, ok := clockField.(string)
+		if !ok {
+			return utils.ErrInvalidQuietHours
+		}
+		if _, err := models.ParseClockTime(clock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
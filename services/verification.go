@@ -0,0 +1,89 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/channels"
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// verificationTokenBytes is the number of random bytes in an email verification token.
+const verificationTokenBytes = 32
+
+type VerificationService struct {
+	verificationRepository *repositories.VerificationRepository
+	userRepository         *repositories.UserRepository
+	emailChannel           *channels.EmailChannel
+	tokenTTL               time.Duration
+}
+
+// NewVerificationService creates an email verification service. emailChannel is nil when email is
+// not configured, in which case addresses cannot be confirmed and stay unverified.
+func NewVerificationService(verificationRepository *repositories.VerificationRepository, userRepository *repositories.UserRepository, emailChannel *channels.EmailChannel, tokenTTL time.Duration) *VerificationService {
+	return &VerificationService{
+		verificationRepository: verificationRepository,
+		userRepository:         userRepository,
+		emailChannel:           emailChannel,
+		tokenTTL:               tokenTTL,
+	}
+}
+
+// SendVerification emails a user a single-use token that confirms their email address. Tokens sent
+// earlier stop working.
+func (s *VerificationService) SendVerification(user *models.UserProfile) error {
+	if user.EmailVerifiedAt != nil {
+		return utils.ErrEmailAlreadyVerified
+	}
+	if s.emailChannel == nil {
+		return utils.ErrVerificationUnavailable
+	}
+
+	token, err := utils.GenerateSecret(verificationTokenBytes)
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(s.tokenTTL)
+	err = s.verificationRepository.CreateVerificationToken(user.ID, utils.HashToken(token), expiresAt)
+	if err != nil {
+		return err
+	}
+	return s.emailChannel.SendVerification(user, token, expiresAt)
+}
+
+// ResendVerification sends a user a new verification token.
+func (s *VerificationService) ResendVerification(userID int64) error {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	return s.SendVerification(user)
+}
+
+// VerifyEmail confirms the email address a token was sent to and uses the token up.
+func (s *VerificationService) VerifyEmail(token string) error {
+	if token == "" {
+		return utils.ErrInvalidVerificationToken
+	}
+	userID, err := s.verificationRepository.VerifyEmail(utils.HashToken(token), time.Now())
+	if err != nil {
+		return err
+	}
+	log.Printf("Email address of user %d was verified\n", userID)
+	return nil
+}
+
+// MarkEmailVerified lets an administrator confirm the email address of a user without a token, for
+// servers where email is not configured.
+func (s *VerificationService) MarkEmailVerified(userID, adminID int64) error {
+	if err := requireAdmin(s.userRepository, adminID); err != nil {
+		return err
+	}
+	if err := s.verificationRepository.MarkEmailVerified(userID, time.Now()); err != nil {
+		return err
+	}
+	log.Printf("Email address of user %d was verified by administrator %d\n", userID, adminID)
+	return nil
+}
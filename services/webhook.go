@@ -0,0 +1,130 @@
+package services
+
+import (
+	"github.com/akinolaemmanuel49/notify-api/models"
+	"github.com/akinolaemmanuel49/notify-api/repositories"
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// webhookSecretBytes is the size of generated webhook secrets.
+const webhookSecretBytes = 32
+
+type WebhookService struct {
+	webhookRepository *repositories.WebhookRepository
+}
+
+func NewWebhookService(webhookRepository *repositories.WebhookRepository) *WebhookService {
+	return &WebhookService{
+		webhookRepository: webhookRepository,
+	}
+}
+
+// CreateWebhook registers a webhook. The returned webhook is the only place its secret is shown.
+func (s *WebhookService) CreateWebhook(webhookInput *models.WebhookInput, userID int64) (*models.Webhook, error) {
+	if err := s.prepare(webhookInput); err != nil {
+		return nil, err
+	}
+	if webhookInput.Secret == "" {
+		secret, err := utils.GenerateSecret(webhookSecretBytes)
+		if err != nil {
+			return nil, err
+		}
+		webhookInput.Secret = secret
+	}
+	webhook, err := s.webhookRepository.CreateWebhook(webhookInput, userID)
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *WebhookService) GetWebhookByID(ID, userID int64) (*models.Webhook, error) {
+	webhook, err := s.getOwnWebhook(ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+func (s *WebhookService) GetOwnWebhooks(userID int64, page, pageSize int) ([]*models.Webhook, error) {
+	webhooks, err := s.webhookRepository.GetOwnWebhooks(userID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhookByID updates a webhook. An empty secret keeps the current one.
+func (s *WebhookService) UpdateWebhookByID(ID, userID int64, webhookInput *models.WebhookInput) error {
+	if _, err := s.getOwnWebhook(ID, userID); err != nil {
+		return err
+	}
+	if err := s.prepare(webhookInput); err != nil {
+		return err
+	}
+	err := s.webhookRepository.UpdateWebhookByID(ID, webhookInput)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookService) DeleteWebhookByID(ID, userID int64) error {
+	if _, err := s.getOwnWebhook(ID, userID); err != nil {
+		return err
+	}
+	err := s.webhookRepository.DeleteWebhookByID(ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookService) GetWebhookDeliveries(ID, userID int64, page, pageSize int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.getOwnWebhook(ID, userID); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.webhookRepository.GetWebhookDeliveries(ID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookService) getOwnWebhook(ID, userID int64) (*models.Webhook, error) {
+	webhook, err := s.webhookRepository.GetWebhookByID(ID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.UserID != userID {
+		return nil, utils.ErrForbidden
+	}
+	return webhook, nil
+}
+
+// prepare validates a webhook definition and fills in defaults.
+func (s *WebhookService) prepare(webhookInput *models.WebhookInput) error {
+	if err := models.ValidateWebhookURL(webhookInput.URL); err != nil {
+		return err
+	}
+	if webhookInput.Format == "" {
+		webhookInput.Format = models.WebhookFormatJSON
+	}
+	if err := models.ValidateWebhookFormat(webhookInput.Format); err != nil {
+		return err
+	}
+	if webhookInput.Topic != "" {
+		if err := models.ValidateTopicName(webhookInput.Topic); err != nil {
+			return err
+		}
+	}
+	if webhookInput.Active == nil {
+		active := true
+		webhookInput.Active = &active
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Package templating renders notification templates with text/template and a restricted set of functions.
+package templating
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"unicode/utf8"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+// maxOutputBytes caps the size of a rendered template.
+const maxOutputBytes = 64 * 1024
+
+// missingKeyPattern extracts the variable name from the error text/template reports for a missing map key.
+var missingKeyPattern = regexp.MustCompile(`map has no entry for key "([^"]*)"`)
+
+// funcs are the only functions available to templates besides the text/template builtins.
+// None of them has side effects or can reach outside the variables passed in. Functions that can
+// return more than they are given fail once their result exceeds maxOutputBytes, and shadow the
+// builtin escapers for the same reason, so that nesting them cannot build huge intermediate values.
+var funcs = template.FuncMap{
+	"upper": capped(strings.ToUpper),
+	"lower": capped(strings.ToLower),
+	"trim":  strings.TrimSpace,
+	// default returns value unless it is empty, e.g. {{index . "name" | default "there"}}
+	"default": func(fallback, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+	// truncate shortens s to at most n characters
+	"truncate": func(n int, s string) string {
+		if n < 0 || utf8.RuneCountInString(s) <= n {
+			return s
+		}
+		return string([]rune(s)[:n])
+	},
+	"html":     cappedEscaper(template.HTMLEscaper),
+	"js":       cappedEscaper(template.JSEscaper),
+	"urlquery": cappedEscaper(template.URLQueryEscaper),
+}
+
+// disallowedFuncs are the builtins templates cannot call. Their format widths and variadic
+// arguments build strings of any size from a short template, e.g. {{printf "%0999999999d" 0}}.
+var disallowedFuncs = map[string]bool{
+	"print":   true,
+	"printf":  true,
+	"println": true,
+}
+
+// capped makes f fail with ErrTemplateTooLarge when its result exceeds maxOutputBytes.
+func capped(f func(string) string) func(string) (string, error) {
+	return func(s string) (string, error) {
+		return checkSize(f(s))
+	}
+}
+
+// cappedEscaper makes one of the text/template escapers fail with ErrTemplateTooLarge when its
+// result exceeds maxOutputBytes.
+func cappedEscaper(escape func(...interface{}) string) func(...interface{}) (string, error) {
+	return func(args ...interface{}) (string, error) {
+		return checkSize(escape(args...))
+	}
+}
+
+func checkSize(s string) (string, error) {
+	if len(s) > maxOutputBytes {
+		return "", utils.ErrTemplateTooLarge
+	}
+	return s, nil
+}
+
+func compile(text string) (*template.Template, error) {
+	tmpl, err := template.New("template").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", utils.ErrInvalidTemplate, err.Error())
+	}
+	// Without loops or nested templates every action runs at most once, and without variables or
+	// unbounded functions no intermediate value exceeds maxOutputBytes, so rendering takes time and
+	// memory proportional to the size of the template
+	if len(tmpl.Templates()) > 1 {
+		return nil, fmt.Errorf("%w: define and block are not allowed", utils.ErrInvalidTemplate)
+	}
+	if tmpl.Tree != nil {
+		if err := checkNode(tmpl.Tree.Root); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// checkNode rejects the actions that can make rendering loop: range, which iterates over integers
+// since Go 1.22, and template, which can call itself. It also rejects variables, which would let a
+// template reuse a value it has built, and calls to disallowedFuncs.
+func checkNode(node parse.Node) error {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return nil
+		}
+		for _, child := range node.Nodes {
+			if err := checkNode(child); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkNode(node.Pipe)
+	case *parse.IfNode:
+		return checkBranch(&node.BranchNode)
+	case *parse.WithNode:
+		return checkBranch(&node.BranchNode)
+	case *parse.RangeNode:
+		return fmt.Errorf("%w: range is not allowed", utils.ErrInvalidTemplate)
+	case *parse.TemplateNode:
+		return fmt.Errorf("%w: template is not allowed", utils.ErrInvalidTemplate)
+	case *parse.PipeNode:
+		if node == nil {
+			return nil
+		}
+		if len(node.Decl) > 0 {
+			return fmt.Errorf("%w: variables are not allowed", utils.ErrInvalidTemplate)
+		}
+		for _, command := range node.Cmds {
+			if err := checkNode(command); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			if err := checkNode(arg); err != nil {
+				return err
+			}
+		}
+	case *parse.ChainNode:
+		return checkNode(node.Node)
+	case *parse.VariableNode:
+		return fmt.Errorf("%w: variables are not allowed", utils.ErrInvalidTemplate)
+	case *parse.IdentifierNode:
+		if disallowedFuncs[node.Ident] {
+			return fmt.Errorf("%w: %s is not allowed", utils.ErrInvalidTemplate, node.Ident)
+		}
+	}
+	return nil
+}
+
+func checkBranch(branch *parse.BranchNode) error {
+	if err := checkNode(branch.Pipe); err != nil {
+		return err
+	}
+	if err := checkNode(branch.List); err != nil {
+		return err
+	}
+	return checkNode(branch.ElseList)
+}
+
+// Validate checks that text is a well-formed template.
+func Validate(text string) error {
+	_, err := compile(text)
+	return err
+}
+
+// Render executes a template with variables. Variables must be strings, numbers, booleans or null.
+// A variable that the template uses but that is not given is reported as ErrMissingTemplateVariable.
+func Render(text string, variables map[string]interface{}) (string, error) {
+	for name, value := range variables {
+		switch value.(type) {
+		case nil, string, float64, bool:
+		default:
+			return "", fmt.Errorf("%w: %s", utils.ErrInvalidTemplateVariable, name)
+		}
+	}
+	if variables == nil {
+		variables = map[string]interface{}{}
+	}
+
+	tmpl, err := compile(text)
+	if err != nil {
+		return "", err
+	}
+	output := &limitedBuilder{limit: maxOutputBytes}
+	if err := tmpl.Execute(output, variables); err != nil {
+		if errors.Is(err, utils.ErrTemplateTooLarge) {
+			return "", utils.ErrTemplateTooLarge
+		}
+		if match := missingKeyPattern.FindStringSubmatch(err.Error()); match != nil {
+			return "", fmt.Errorf("%w: %s", utils.ErrMissingTemplateVariable, match[1])
+		}
+		return "", fmt.Errorf("%w: %s", utils.ErrInvalidTemplate, err.Error())
+	}
+	return output.String(), nil
+}
+
+// limitedBuilder is a strings.Builder that fails once more than limit bytes are written.
+type limitedBuilder struct {
+	strings.Builder
+	limit int
+}
+
+func (b *limitedBuilder) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, utils.ErrTemplateTooLarge
+	}
+	return b.Builder.Write(p)
+}
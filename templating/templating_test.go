@@ -0,0 +1,128 @@
+package templating
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akinolaemmanuel49/notify-api/utils"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		text      string
+		variables map[string]interface{}
+		want      string
+	}{
+		{"Deploy of {{.service}} finished", map[string]interface{}{"service": "api"}, "Deploy of api finished"},
+		{"{{upper .env}}", map[string]interface{}{"env": "prod"}, "PROD"},
+		{`Hi {{index . "name" | default "there"}}`, nil, "Hi there"},
+		{"{{truncate 3 .summary}}", map[string]interface{}{"summary": "abcdef"}, "abc"},
+		{"{{if .failed}}failed{{else}}ok{{end}}", map[string]interface{}{"failed": false}, "ok"},
+		{"{{with .n}}{{.}}{{end}}", map[string]interface{}{"n": 3.0}, "3"},
+		{"{{html .name}}", map[string]interface{}{"name": "<b>"}, "&lt;b&gt;"},
+		{"{{len .name}}", map[string]interface{}{"name": "abc"}, "3"},
+	}
+	for _, tt := range tests {
+		got, err := Render(tt.text, tt.variables)
+		if err != nil {
+			t.Errorf("Render(%q): unexpected error %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Render(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMissingVariable(t *testing.T) {
+	_, err := Render("Deploy of {{.service}} to {{.environment}}", map[string]interface{}{"service": "api"})
+	if !errors.Is(err, utils.ErrMissingTemplateVariable) {
+		t.Fatalf("got error %v, want ErrMissingTemplateVariable", err)
+	}
+	if !strings.HasSuffix(err.Error(), ": environment") {
+		t.Errorf("error %q does not name the missing variable", err)
+	}
+}
+
+func TestRenderInvalidVariable(t *testing.T) {
+	_, err := Render("{{.tags}}", map[string]interface{}{"tags": []interface{}{"a"}})
+	if !errors.Is(err, utils.ErrInvalidTemplateVariable) {
+		t.Fatalf("got error %v, want ErrInvalidTemplateVariable", err)
+	}
+}
+
+func TestRenderSizeCap(t *testing.T) {
+	value := strings.Repeat("x", maxOutputBytes/4)
+
+	got, err := Render("{{.v}}{{.v}}{{.v}}{{.v}}", map[string]interface{}{"v": value})
+	if err != nil || len(got) != maxOutputBytes {
+		t.Fatalf("output of exactly the limit: got %d bytes and error %v", len(got), err)
+	}
+	_, err = Render("{{.v}}{{.v}}{{.v}}{{.v}}!", map[string]interface{}{"v": value})
+	if !errors.Is(err, utils.ErrTemplateTooLarge) {
+		t.Fatalf("output over the limit: got error %v, want ErrTemplateTooLarge", err)
+	}
+}
+
+func TestValidateRejectsLoops(t *testing.T) {
+	for _, text := range []string{
+		"{{range 100000}}{{range 100000}}{{range 100000}}{{end}}{{end}}{{end}}",
+		"{{range .}}{{.}}{{end}}",
+		"{{if true}}{{range 3}}x{{end}}{{end}}",
+		"{{with .a}}{{else}}{{range 3}}x{{end}}{{end}}",
+		`{{define "a"}}{{template "a" .}}{{end}}{{template "a" .}}`,
+		`{{block "a" .}}x{{end}}`,
+	} {
+		if err := Validate(text); !errors.Is(err, utils.ErrInvalidTemplate) {
+			t.Errorf("Validate(%q): got error %v, want ErrInvalidTemplate", text, err)
+		}
+		if _, err := Render(text, nil); !errors.Is(err, utils.ErrInvalidTemplate) {
+			t.Errorf("Render(%q): got error %v, want ErrInvalidTemplate", text, err)
+		}
+	}
+}
+
+func TestValidateRejectsUnboundedValues(t *testing.T) {
+	for _, text := range []string{
+		`{{$a := printf "%0999999d" 0}}{{$b := printf "%s%s%s%s%s%s%s%s" $a $a $a $a $a $a $a $a}}` +
+			`{{$c := printf "%s%s%s%s%s%s%s%s" $b $b $b $b $b $b $b $b}}{{len $c}}`,
+		`{{$a := .v}}{{$a}}`,
+		`{{with .v}}{{$a := .}}{{end}}`,
+		`{{if true}}{{len $}}{{end}}`,
+		`{{printf "%0999999999d" 0}}`,
+		`{{len (print .v .v)}}`,
+		`{{.v | println}}`,
+		`{{with .v}}{{else}}{{printf "%s" .}}{{end}}`,
+	} {
+		if err := Validate(text); !errors.Is(err, utils.ErrInvalidTemplate) {
+			t.Errorf("Validate(%q): got error %v, want ErrInvalidTemplate", text, err)
+		}
+	}
+}
+
+func TestRenderCapsIntermediateValues(t *testing.T) {
+	value := strings.Repeat("&", maxOutputBytes/2)
+
+	// Each template renders only a number, but builds a value over the limit on the way
+	for _, text := range []string{
+		"{{len (html .v .v .v)}}",
+		"{{len (html (html (html (html (html .v)))))}}",
+		"{{with html .v}}{{with html . . .}}{{len .}}{{end}}{{end}}",
+		"{{len (urlquery .v .v .v)}}",
+		"{{len (js .v .v .v)}}",
+	} {
+		if _, err := Render(text, map[string]interface{}{"v": value}); !errors.Is(err, utils.ErrTemplateTooLarge) {
+			t.Errorf("Render(%q): got error %v, want ErrTemplateTooLarge", text, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("{{.a"); !errors.Is(err, utils.ErrInvalidTemplate) {
+		t.Errorf("unterminated action: got error %v, want ErrInvalidTemplate", err)
+	}
+	if err := Validate("{{/* comment */}}{{.a}}"); err != nil {
+		t.Errorf("comment: unexpected error %v", err)
+	}
+}
@@ -9,7 +9,6 @@ import (
 )
 
 var cfg config.Config
-var privateKey = []byte(cfg.JWT.Key)
 
 func RespondWithError(w http.ResponseWriter, errorMessage string, code int) {
 	http.Error(w, errorMessage, code)
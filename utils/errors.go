@@ -5,11 +5,57 @@ import (
 )
 
 var (
-	ErrNotFound                = errors.New("record does not exist")
-	ErrInvalidCredentials      = errors.New("invalid credentials")
-	ErrInvalidRangeForPriority = errors.New("priority must be between Low [0] and High [2]")
-	ErrInvalidTypeForPriority  = errors.New("priority must be an integer")
-	ErrInvalidValueForPriority = errors.New("invalid value")
-	ErrDuplicateKey            = errors.New("email address already in use")
-	ErrForbidden               = errors.New("you are not permitted to modify this resource")
+	ErrNotFound                  = errors.New("record does not exist")
+	ErrInvalidCredentials        = errors.New("invalid credentials")
+	ErrInvalidRangeForPriority   = errors.New("priority must be between Low [0] and High [2]")
+	ErrInvalidTypeForPriority    = errors.New("priority must be an integer")
+	ErrInvalidValueForPriority   = errors.New("invalid value")
+	ErrDuplicateKey              = errors.New("email address already in use")
+	ErrForbidden                 = errors.New("you are not permitted to modify this resource")
+	ErrRecipientNotFound         = errors.New("one or more recipients do not exist")
+	ErrInvalidTopicName          = errors.New("topic name must be 1-64 lowercase letters, digits, '.', '_' or '-'")
+	ErrTopicExists               = errors.New("topic already exists")
+	ErrInvalidCronExpression     = errors.New("invalid cron expression")
+	ErrInvalidTimezone           = errors.New("invalid timezone")
+	ErrInvalidCatchUpPolicy      = errors.New("catch-up policy must be one of skip, once or all")
+	ErrInvalidExpiry             = errors.New("notification must expire after it is sent")
+	ErrInvalidSendAt             = errors.New("send_at must be an RFC 3339 time in the future")
+	ErrNotificationDispatched    = errors.New("notification was already sent, its send_at cannot change")
+	ErrInvalidWebhookURL         = errors.New("webhook url must be an absolute http or https url")
+	ErrWebhookURLNotPublic       = errors.New("webhook url must point to a public address")
+	ErrInvalidWebhookFormat      = errors.New("webhook format must be one of json, slack, discord or teams")
+	ErrTopicNotFound             = errors.New("topic does not exist")
+	ErrInvalidPriorityLabel      = errors.New("priority must be one of LOW, MID or HIGH")
+	ErrUnknownChannel            = errors.New("unknown delivery channel")
+	ErrInvalidQuietHours         = errors.New("quiet hours must be given as HH:MM")
+	ErrInvalidDigestFrequency    = errors.New("digest frequency must be one of off, daily or weekly")
+	ErrInvalidTemplate           = errors.New("invalid template")
+	ErrInvalidTemplateName       = errors.New("template name must be a lowercase slug")
+	ErrInvalidTemplateVariable   = errors.New("template variables must be strings, numbers or booleans")
+	ErrMissingTemplateVariable   = errors.New("missing template variable")
+	ErrTemplateTooLarge          = errors.New("rendered template is too large")
+	ErrTemplateExists            = errors.New("template already exists")
+	ErrTemplateNotFound          = errors.New("template does not exist")
+	ErrTemplateConflict          = errors.New("a notification takes either a template or a title and message")
+	ErrInvalidLocale             = errors.New("invalid locale")
+	ErrInvalidTranslations       = errors.New("translations must map locales to a title and message")
+	ErrInvalidIdempotencyKey     = errors.New("idempotency key must be 1-255 printable ascii characters")
+	ErrIdempotencyKeyMismatch    = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInUse       = errors.New("a request with this idempotency key is still in progress")
+	ErrInvalidVerificationToken  = errors.New("verification token is invalid or has expired")
+	ErrEmailNotVerified          = errors.New("email address has not been verified")
+	ErrEmailAlreadyVerified      = errors.New("email address is already verified")
+	ErrVerificationUnavailable   = errors.New("email verification is not available because email is not configured")
+	ErrInvalidPasswordResetToken = errors.New("password reset token is invalid or has expired")
+	ErrPasswordResetUnavailable  = errors.New("password reset is not available because email is not configured")
+	ErrPasswordTooShort          = errors.New("password is too short")
+	ErrPasswordTooLong           = errors.New("password is too long")
+	ErrPasswordBreached          = errors.New("password appears in a list of breached passwords, choose another one")
+	ErrIncorrectPassword         = errors.New("current password is incorrect")
+	ErrInvalidRefreshToken       = errors.New("refresh token is invalid or has expired")
+	ErrRefreshTokenReused        = errors.New("refresh token was already used, every token of its session has been revoked")
+	ErrInvalidSignature          = errors.New("invalid webhook signature")
+	ErrAdminRequired             = errors.New("administrator privileges required")
+	ErrUnknownUserField          = errors.New("field does not exist or cannot be updated")
+	ErrUnknownNotificationField  = errors.New("field does not exist or cannot be updated")
 )
@@ -2,7 +2,6 @@ package utils
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,19 +10,45 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateJWT(ID int64) (string, error) {
+// GenerateJWT issues an access token for a user and returns it with its expiry. Every token gets a
+// unique "jti" so that it can be revoked, and the ID of the session (refresh token family) it
+// belongs to as "sid". Tokens signed with an asymmetric key name it in their "kid" header.
+func GenerateJWT(ID int64, sessionID string) (string, time.Time, error) {
 	LoadEnv()
 
 	cfg.ReadFile("dev-config.yml") // For use in development
 	cfg.ReadEnv()
 
-	tokenTTL, _ := strconv.Atoi(cfg.JWT.Token_TTL)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	tokenTTL, err := strconv.Atoi(cfg.JWT.Token_TTL)
+	if err != nil {
+		tokenTTL = 900 // Set a default value (assuming TTL is in seconds)
+	}
+	tokenID, err := GenerateSecret(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if signingKey == nil {
+		return "", time.Time{}, errors.New("no JWT signing key configured")
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Second * time.Duration(tokenTTL))
+	token := jwt.NewWithClaims(signingKey.method, jwt.MapClaims{
 		"id":  ID,
-		"iat": time.Now().Unix(),
-		"exp": time.Now().Add(time.Second * time.Duration(tokenTTL)).Unix(),
+		"jti": tokenID,
+		"sid": sessionID,
+		"iat": now.Unix(),
+		"exp": expiresAt.Unix(),
 	})
-	return token.SignedString(privateKey)
+	if signingKey.id != "" {
+		token.Header["kid"] = signingKey.id
+	}
+	signedToken, err := token.SignedString(signingKey.signingKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signedToken, expiresAt, nil
 }
 
 func ValidateJWT(w http.ResponseWriter, r *http.Request) error {
@@ -49,11 +74,6 @@ func ExtractTokenFromHeader(r *http.Request) string {
 
 func GetToken(r *http.Request) (*jwt.Token, error) {
 	tokenString := ExtractTokenFromHeader(r)
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return privateKey, nil
-	})
+	token, err := jwt.Parse(tokenString, jwtVerificationKey)
 	return token, err
 }
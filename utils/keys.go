@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported JWT signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for signing or verifying tokens.
+const minRSAKeyBits = 2048
+
+// jwtKey is a key tokens are signed or verified with. Keys loaded only for verification have no
+// signing key.
+type jwtKey struct {
+	id              string
+	method          jwt.SigningMethod
+	signingKey      interface{}
+	verificationKey interface{}
+}
+
+// JSONWebKey is the public part of a verification key, as published in the JWKS (RFC 7517).
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+var (
+	signingKey       *jwtKey
+	verificationKeys = map[string]*jwtKey{}
+)
+
+// LoadJWTKeys configures the keys access tokens are signed and verified with. HS256 signs with the
+// shared secret. RS256 and EdDSA sign with the PEM encoded private key at signingKeyPath, and also
+// accept tokens signed by the private keys of the PEM encoded public keys at verificationKeyPaths,
+// so that a key can be rotated out without invalidating the tokens it has already signed.
+func LoadJWTKeys(algorithm, secret, signingKeyPath string, verificationKeyPaths []string) error {
+	keys := map[string]*jwtKey{}
+	var key *jwtKey
+
+	switch algorithm {
+	case "", AlgorithmHS256:
+		if secret == "" {
+			return errors.New("a JWT key is required for HS256")
+		}
+		key = &jwtKey{method: jwt.SigningMethodHS256, signingKey: []byte(secret), verificationKey: []byte(secret)}
+		keys[key.id] = key
+		signingKey, verificationKeys = key, keys
+		return nil
+	case AlgorithmRS256, AlgorithmEdDSA:
+	default:
+		return fmt.Errorf("unsupported JWT algorithm %q", algorithm)
+	}
+
+	if signingKeyPath == "" {
+		return fmt.Errorf("a signing key is required for %s", algorithm)
+	}
+	data, err := os.ReadFile(signingKeyPath)
+	if err != nil {
+		return err
+	}
+	if algorithm == AlgorithmRS256 {
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", signingKeyPath, err)
+		}
+		key, err = newJWTKey(&privateKey.PublicKey)
+		if err != nil {
+			return fmt.Errorf("%s: %w", signingKeyPath, err)
+		}
+		key.signingKey = privateKey
+	} else {
+		privateKey, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", signingKeyPath, err)
+		}
+		key, err = newJWTKey(privateKey.(ed25519.PrivateKey).Public())
+		if err != nil {
+			return fmt.Errorf("%s: %w", signingKeyPath, err)
+		}
+		key.signingKey = privateKey
+	}
+	keys[key.id] = key
+
+	for _, path := range verificationKeyPaths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		verificationKey, err := loadVerificationKey(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if _, ok := keys[verificationKey.id]; !ok {
+			keys[verificationKey.id] = verificationKey
+		}
+	}
+
+	signingKey, verificationKeys = key, keys
+	return nil
+}
+
+// loadVerificationKey reads a PEM encoded RSA or Ed25519 public key.
+func loadVerificationKey(path string) (*jwtKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return newJWTKey(publicKey)
+	}
+	publicKey, err := jwt.ParseEdPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, errors.New("not a PEM encoded RSA or Ed25519 public key")
+	}
+	return newJWTKey(publicKey)
+}
+
+// newJWTKey returns a verification key identified by its JWK thumbprint.
+func newJWTKey(publicKey interface{}) (*jwtKey, error) {
+	key := &jwtKey{verificationKey: publicKey}
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		if publicKey.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+		}
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", publicKey)
+	}
+	key.id = thumbprint(key.jsonWebKey())
+	return key, nil
+}
+
+// jsonWebKey returns the public part of the key as a JWK.
+func (k *jwtKey) jsonWebKey() JSONWebKey {
+	jwk := JSONWebKey{Use: "sig", Algorithm: k.method.Alg(), KeyID: k.id}
+	switch publicKey := k.verificationKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+	return jwk
+}
+
+// thumbprint computes the RFC 7638 thumbprint of a JWK: the SHA-256 of its required members,
+// serialized in lexicographic order without whitespace.
+func thumbprint(jwk JSONWebKey) string {
+	var canonical string
+	switch jwk.KeyType {
+	case "RSA":
+		canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	case "OKP":
+		canonical = fmt.Sprintf(`{"crv":"%s","kty":"OKP","x":"%s"}`, jwk.Curve, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// JSONWebKeys returns the public keys tokens can be verified with, signing key first. It is empty
+// when tokens are signed with a shared secret, which must never be published.
+func JSONWebKeys() []JSONWebKey {
+	jwks := []JSONWebKey{}
+	if signingKey == nil || signingKey.id == "" {
+		return jwks
+	}
+	jwks = append(jwks, signingKey.jsonWebKey())
+
+	var others []JSONWebKey
+	for id, key := range verificationKeys {
+		if id != signingKey.id {
+			others = append(others, key.jsonWebKey())
+		}
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i].KeyID < others[j].KeyID })
+	return append(jwks, others...)
+}
+
+// jwtVerificationKey is the keyfunc tokens are parsed with. It selects the verification key named
+// by the token's "kid" header, and refuses tokens signed with any other algorithm than that key's.
+func jwtVerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := verificationKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.verificationKey, nil
+}
@@ -0,0 +1,301 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// The example keys of RFC 7638 section 3.1 and RFC 8037 appendix A.3, with their thumbprints.
+const (
+	rfc7638N          = "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"
+	rfc7638Thumbprint = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+	rfc8037X          = "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+	rfc8037Thumbprint = "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+)
+
+// testKeys are PEM files of keys generated for a test.
+type testKeys struct {
+	rsaPrivate, rsaPublic         string
+	oldRSAPrivate, oldRSAPublic   string
+	ed25519Private, ed25519Public string
+	rsaPublicPEM                  []byte
+}
+
+func generateTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	dir := t.TempDir()
+	write := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	marshalPublic := func(publicKey interface{}) []byte {
+		der, err := x509.MarshalPKIXPublicKey(publicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der
+	}
+
+	keys := &testKeys{}
+	for _, name := range []string{"rsa", "old-rsa"} {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		privatePath := write(name+".pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey))
+		publicPath := write(name+".pub.pem", "PUBLIC KEY", marshalPublic(&privateKey.PublicKey))
+		if name == "rsa" {
+			keys.rsaPrivate, keys.rsaPublic = privatePath, publicPath
+		} else {
+			keys.oldRSAPrivate, keys.oldRSAPublic = privatePath, publicPath
+		}
+	}
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys.ed25519Private = write("ed25519.pem", "PRIVATE KEY", der)
+	keys.ed25519Public = write("ed25519.pub.pem", "PUBLIC KEY", marshalPublic(publicKey))
+
+	keys.rsaPublicPEM, err = os.ReadFile(keys.rsaPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+// loadTestJWTKeys configures JWT keys for the rest of a test and restores the previous ones afterwards.
+func loadTestJWTKeys(t *testing.T, algorithm, secret, signingKeyPath string, verificationKeyPaths ...string) {
+	t.Helper()
+	previousSigningKey, previousVerificationKeys := signingKey, verificationKeys
+	t.Cleanup(func() { signingKey, verificationKeys = previousSigningKey, previousVerificationKeys })
+	if err := LoadJWTKeys(algorithm, secret, signingKeyPath, verificationKeyPaths); err != nil {
+		t.Fatalf("LoadJWTKeys: %v", err)
+	}
+}
+
+func generateTestJWT(t *testing.T) string {
+	t.Helper()
+	token, _, err := GenerateJWT(1, "session")
+	if err != nil {
+		t.Fatalf("GenerateJWT: %v", err)
+	}
+	return token
+}
+
+func parseTestJWT(token string) error {
+	_, err := jwt.Parse(token, jwtVerificationKey)
+	return err
+}
+
+func TestThumbprint(t *testing.T) {
+	n, err := base64.RawURLEncoding.DecodeString(rfc7638N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(rfc8037X)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		publicKey interface{}
+		want      string
+	}{
+		{"RSA", &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}, rfc7638Thumbprint},
+		{"Ed25519", ed25519.PublicKey(x), rfc8037Thumbprint},
+	}
+	for _, tt := range tests {
+		key, err := newJWTKey(tt.publicKey)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if key.id != tt.want {
+			t.Errorf("%s: kid = %q, want %q", tt.name, key.id, tt.want)
+		}
+	}
+}
+
+func TestNewJWTKeyRejectsShortRSAKeys(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newJWTKey(&privateKey.PublicKey); err == nil {
+		t.Error("a 1024 bit RSA key was accepted")
+	}
+}
+
+func TestJSONWebKeys(t *testing.T) {
+	keys := generateTestKeys(t)
+
+	loadTestJWTKeys(t, AlgorithmHS256, "secret", "")
+	if jwks := JSONWebKeys(); len(jwks) != 0 {
+		t.Errorf("HS256: published %d keys, want none", len(jwks))
+	}
+
+	loadTestJWTKeys(t, AlgorithmRS256, "", keys.rsaPrivate, keys.ed25519Public, keys.oldRSAPublic, keys.rsaPublic)
+	jwks := JSONWebKeys()
+	if len(jwks) != 3 {
+		t.Fatalf("published %d keys, want 3", len(jwks))
+	}
+	if jwks[0].KeyID != signingKey.id {
+		t.Errorf("first key is %q, want the signing key %q", jwks[0].KeyID, signingKey.id)
+	}
+	if jwks[1].KeyID > jwks[2].KeyID {
+		t.Errorf("verification keys are not ordered by kid: %q, %q", jwks[1].KeyID, jwks[2].KeyID)
+	}
+	for _, jwk := range jwks {
+		if jwk.Use != "sig" || jwk.KeyID != thumbprint(jwk) {
+			t.Errorf("key %q: use %q, thumbprint %q", jwk.KeyID, jwk.Use, thumbprint(jwk))
+		}
+		switch jwk.KeyType {
+		case "RSA":
+			if jwk.Algorithm != AlgorithmRS256 || jwk.N == "" || jwk.E != "AQAB" || jwk.X != "" || jwk.Curve != "" {
+				t.Errorf("RSA key %q serialized as %+v", jwk.KeyID, jwk)
+			}
+		case "OKP":
+			if jwk.Algorithm != AlgorithmEdDSA || jwk.Curve != "Ed25519" || len(jwk.X) != 43 || jwk.N != "" || jwk.E != "" {
+				t.Errorf("Ed25519 key %q serialized as %+v", jwk.KeyID, jwk)
+			}
+		default:
+			t.Errorf("key %q has type %q", jwk.KeyID, jwk.KeyType)
+		}
+	}
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	keys := generateTestKeys(t)
+
+	loadTestJWTKeys(t, AlgorithmRS256, "", keys.oldRSAPrivate)
+	oldRSAToken := generateTestJWT(t)
+	loadTestJWTKeys(t, AlgorithmEdDSA, "", keys.ed25519Private)
+	ed25519Token := generateTestJWT(t)
+	loadTestJWTKeys(t, AlgorithmRS256, "", keys.rsaPrivate)
+	rsaToken := generateTestJWT(t)
+
+	tests := []struct {
+		name               string
+		algorithm          string
+		signingKey         string
+		verificationKeys   []string
+		accepted, rejected []string
+	}{
+		{
+			name:       "signing key only",
+			algorithm:  AlgorithmRS256,
+			signingKey: keys.rsaPrivate,
+			accepted:   []string{rsaToken},
+			rejected:   []string{oldRSAToken, ed25519Token},
+		},
+		{
+			name:             "rotated from an RSA key",
+			algorithm:        AlgorithmRS256,
+			signingKey:       keys.rsaPrivate,
+			verificationKeys: []string{keys.oldRSAPublic},
+			accepted:         []string{rsaToken, oldRSAToken},
+			rejected:         []string{ed25519Token},
+		},
+		{
+			name:             "rotated to Ed25519",
+			algorithm:        AlgorithmEdDSA,
+			signingKey:       keys.ed25519Private,
+			verificationKeys: []string{keys.rsaPublic, keys.oldRSAPublic},
+			accepted:         []string{ed25519Token, rsaToken, oldRSAToken},
+		},
+		{
+			name:       "rotated key retired",
+			algorithm:  AlgorithmEdDSA,
+			signingKey: keys.ed25519Private,
+			accepted:   []string{ed25519Token},
+			rejected:   []string{rsaToken, oldRSAToken},
+		},
+	}
+	for _, tt := range tests {
+		loadTestJWTKeys(t, tt.algorithm, "", tt.signingKey, tt.verificationKeys...)
+		for i, token := range tt.accepted {
+			if err := parseTestJWT(token); err != nil {
+				t.Errorf("%s: accepted token %d: unexpected error %v", tt.name, i, err)
+			}
+		}
+		for i, token := range tt.rejected {
+			if err := parseTestJWT(token); err == nil {
+				t.Errorf("%s: rejected token %d was accepted", tt.name, i)
+			}
+		}
+	}
+}
+
+func TestJWTVerificationRejectsForgedTokens(t *testing.T) {
+	keys := generateTestKeys(t)
+
+	// A token signed by a key that is not configured, but naming it
+	loadTestJWTKeys(t, AlgorithmRS256, "", keys.oldRSAPrivate)
+	unknownKeyToken := generateTestJWT(t)
+
+	loadTestJWTKeys(t, AlgorithmRS256, "", keys.rsaPrivate)
+	kid := signingKey.id
+	rsaPublicKey := signingKey.verificationKey.(*rsa.PublicKey)
+	claims := jwt.MapClaims{"id": 1, "exp": time.Now().Add(time.Hour).Unix()}
+	sign := func(method jwt.SigningMethod, key interface{}, kid string) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		// Algorithm confusion: an HMAC keyed with the public key, which anyone can compute
+		{"HS256 with the public key PEM", sign(jwt.SigningMethodHS256, keys.rsaPublicPEM, kid)},
+		{"HS256 with the public modulus", sign(jwt.SigningMethodHS256, rsaPublicKey.N.Bytes(), kid)},
+		{"none", sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, kid)},
+		{"unknown kid", unknownKeyToken},
+		{"made-up kid", sign(jwt.SigningMethodHS256, []byte("secret"), "made-up")},
+		{"missing kid", sign(jwt.SigningMethodHS256, []byte("secret"), "")},
+	}
+	for _, tt := range tests {
+		if err := parseTestJWT(tt.token); err == nil {
+			t.Errorf("%s: forged token was accepted", tt.name)
+		}
+	}
+
+	// With a shared secret, tokens carry no kid and must be signed with the secret
+	loadTestJWTKeys(t, AlgorithmHS256, "secret", "")
+	if err := parseTestJWT(generateTestJWT(t)); err != nil {
+		t.Errorf("HS256: unexpected error %v", err)
+	}
+	if err := parseTestJWT(sign(jwt.SigningMethodHS256, []byte("other secret"), "")); err == nil {
+		t.Error("HS256: token signed with another secret was accepted")
+	}
+	if err := parseTestJWT(sign(jwt.SigningMethodHS512, []byte("secret"), "")); err == nil {
+		t.Error("HS256: token signed with HS512 was accepted")
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"net/netip"
+)
+
+// nonPublicPrefixes lists special-purpose ranges that are not covered by the netip.Addr predicates
+// used in IsPublicIP.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // Documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // Documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // Documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reserved, including the broadcast address
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, which can reach private IPv4 addresses
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentation
+}
+
+// IsPublicIP reports whether addr is a globally routable unicast address, as opposed to a loopback,
+// private, link-local (including cloud metadata services), multicast or otherwise reserved one.
+func IsPublicIP(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"notification.created"}`)
+	header := SignPayload("secret", now.Unix(), body)
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr bool
+	}{
+		{"valid", "secret", header, body, now, false},
+		{"within tolerance", "secret", header, body, now.Add(4 * time.Minute), false},
+		{"additional signature", "secret", header + ",v1=deadbeef", body, now, false},
+		{"wrong secret", "other", header, body, now, true},
+		{"tampered body", "secret", header, []byte(`{"event":"x"}`), now, true},
+		{"too old", "secret", header, body, now.Add(6 * time.Minute), true},
+		{"from the future", "secret", header, body, now.Add(-6 * time.Minute), true},
+		{"missing signature", "secret", strings.Split(header, ",")[0], body, now, true},
+		{"malformed timestamp", "secret", "t=abc,v1=00", body, now, true},
+		{"empty", "secret", "", body, now, true},
+	}
+	for _, tt := range tests {
+		err := VerifySignature(tt.secret, tt.header, tt.body, 5*time.Minute, tt.now)
+		if tt.wantErr && !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: got error %v, want ErrInvalidSignature", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
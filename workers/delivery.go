@@ -0,0 +1,36 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/services"
+)
+
+// DeliveryWorker periodically runs the outbound delivery jobs that are due.
+// Jobs are stored in the database, so queued deliveries survive restarts.
+type DeliveryWorker struct {
+	deliveryService *services.DeliveryService
+	interval        time.Duration
+}
+
+func NewDeliveryWorker(deliveryService *services.DeliveryService, interval time.Duration) *DeliveryWorker {
+	return &DeliveryWorker{
+		deliveryService: deliveryService,
+		interval:        interval,
+	}
+}
+
+// Run processes due delivery jobs until the context is cancelled.
+func (d *DeliveryWorker) Run(ctx context.Context) {
+	runPeriodically(ctx, "Delivery worker", d.interval, func() {
+		processed, err := d.deliveryService.ProcessDueJobs()
+		if err != nil {
+			log.Println("Error processing delivery jobs:", err)
+		}
+		if processed > 0 {
+			log.Printf("Processed %d delivery jobs\n", processed)
+		}
+	})
+}
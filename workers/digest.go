@@ -0,0 +1,35 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/services"
+)
+
+// DigestWorker periodically sends the daily and weekly digests that are due.
+type DigestWorker struct {
+	digestService *services.DigestService
+	interval      time.Duration
+}
+
+func NewDigestWorker(digestService *services.DigestService, interval time.Duration) *DigestWorker {
+	return &DigestWorker{
+		digestService: digestService,
+		interval:      interval,
+	}
+}
+
+// Run sends due digests until the context is cancelled.
+func (d *DigestWorker) Run(ctx context.Context) {
+	runPeriodically(ctx, "Digest worker", d.interval, func() {
+		sent, err := d.digestService.SendDueDigests()
+		if err != nil {
+			log.Println("Error sending digests:", err)
+		}
+		if sent > 0 {
+			log.Printf("Sent %d digests\n", sent)
+		}
+	})
+}
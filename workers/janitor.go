@@ -0,0 +1,58 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/services"
+)
+
+// Janitor periodically deletes or archives expired notifications and deletes expired idempotency keys
+// and auth tokens.
+type Janitor struct {
+	notificationService *services.NotificationService
+	idempotencyService  *services.IdempotencyService
+	authService         *services.AuthService
+	interval            time.Duration
+	archive             bool
+}
+
+func NewJanitor(notificationService *services.NotificationService, idempotencyService *services.IdempotencyService, authService *services.AuthService, interval time.Duration, archive bool) *Janitor {
+	return &Janitor{
+		notificationService: notificationService,
+		idempotencyService:  idempotencyService,
+		authService:         authService,
+		interval:            interval,
+		archive:             archive,
+	}
+}
+
+// Run purges expired notifications, idempotency keys and auth tokens until the context is cancelled.
+func (j *Janitor) Run(ctx context.Context) {
+	runPeriodically(ctx, "Janitor", j.interval, func() {
+		purged, err := j.notificationService.PurgeExpiredNotifications(j.archive)
+		if err != nil {
+			log.Println("Error purging expired notifications:", err)
+		}
+		if purged > 0 {
+			log.Printf("Purged %d expired notifications\n", purged)
+		}
+
+		purged, err = j.idempotencyService.PurgeExpiredIdempotencyKeys()
+		if err != nil {
+			log.Println("Error purging expired idempotency keys:", err)
+		}
+		if purged > 0 {
+			log.Printf("Purged %d expired idempotency keys\n", purged)
+		}
+
+		purged, err = j.authService.PurgeExpiredTokens()
+		if err != nil {
+			log.Println("Error purging expired auth tokens:", err)
+		}
+		if purged > 0 {
+			log.Printf("Purged %d expired auth tokens\n", purged)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/services"
+)
+
+// QuietHoursWorker periodically releases the deliveries held for users whose quiet hours or
+// do-not-disturb have ended.
+type QuietHoursWorker struct {
+	deliveryService *services.DeliveryService
+	interval        time.Duration
+}
+
+func NewQuietHoursWorker(deliveryService *services.DeliveryService, interval time.Duration) *QuietHoursWorker {
+	return &QuietHoursWorker{
+		deliveryService: deliveryService,
+		interval:        interval,
+	}
+}
+
+// Run releases held deliveries until the context is cancelled.
+func (q *QuietHoursWorker) Run(ctx context.Context) {
+	runPeriodically(ctx, "Quiet hours worker", q.interval, func() {
+		released, err := q.deliveryService.ReleaseHeldDeliveries()
+		if err != nil {
+			log.Println("Error releasing held deliveries:", err)
+		}
+		if released > 0 {
+			log.Printf("Released %d held deliveries\n", released)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/services"
+)
+
+// RecurrenceGenerator periodically creates the notifications of recurrences that are due.
+type RecurrenceGenerator struct {
+	recurrenceService *services.RecurrenceService
+	interval          time.Duration
+}
+
+func NewRecurrenceGenerator(recurrenceService *services.RecurrenceService, interval time.Duration) *RecurrenceGenerator {
+	return &RecurrenceGenerator{
+		recurrenceService: recurrenceService,
+		interval:          interval,
+	}
+}
+
+// Run fires due recurrences until the context is cancelled.
+func (g *RecurrenceGenerator) Run(ctx context.Context) {
+	runPeriodically(ctx, "Recurrence generator", g.interval, func() {
+		fired, err := g.recurrenceService.FireDueRecurrences()
+		if err != nil {
+			log.Println("Error firing recurrences:", err)
+		}
+		if fired > 0 {
+			log.Printf("Generated %d recurring notifications\n", fired)
+		}
+	})
+}
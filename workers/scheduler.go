@@ -0,0 +1,36 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/akinolaemmanuel49/notify-api/services"
+)
+
+// Scheduler periodically releases scheduled notifications whose send_at time has passed.
+// Pending notifications are read from the database on every run, so nothing is lost across restarts.
+type Scheduler struct {
+	notificationService *services.NotificationService
+	interval            time.Duration
+}
+
+func NewScheduler(notificationService *services.NotificationService, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		notificationService: notificationService,
+		interval:            interval,
+	}
+}
+
+// Run releases due notifications until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	runPeriodically(ctx, "Scheduler", s.interval, func() {
+		released, err := s.notificationService.ReleaseScheduledNotifications()
+		if err != nil {
+			log.Println("Error releasing scheduled notifications:", err)
+		}
+		if released > 0 {
+			log.Printf("Released %d scheduled notifications\n", released)
+		}
+	})
+}
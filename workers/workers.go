@@ -0,0 +1,26 @@
+// Package workers contains the background jobs started from main.
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runPeriodically calls run immediately and then once every interval until the context is cancelled.
+func runPeriodically(ctx context.Context, name string, interval time.Duration, run func()) {
+	log.Println(name, "started")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		run()
+
+		select {
+		case <-ctx.Done():
+			log.Println(name, "stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}